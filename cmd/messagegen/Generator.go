@@ -0,0 +1,716 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	kafkaschema "kafka_schema/schema"
+	"kafka_schema/schema/spec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const generatedHeader = "// Code generated by messagegen. DO NOT EDIT.\n\n"
+
+type Generator struct {
+	packageName string
+	output      string
+	// types maps every generated type to the specification declaring it
+	types map[string]string
+}
+
+func NewGenerator(packageName, output string) *Generator {
+	return &Generator{
+		packageName: packageName,
+		output:      output,
+		types:       make(map[string]string),
+	}
+}
+
+// Generate writes one file per message specification and the helpers they share
+func (g *Generator) Generate(paths ...string) error {
+	for _, path := range paths {
+		message, err := spec.Load(path)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		code, err := g.generateMessage(path, message)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		if err = g.writeFile(message.Name+".go", code); err != nil {
+			return err
+		}
+	}
+	return g.writeFile("MessageUtil.go", []byte(fmt.Sprintf(messageUtil, g.packageName)))
+}
+
+func (g *Generator) writeFile(name string, code []byte) error {
+	formatted, err := format.Source(code)
+	if err != nil {
+		return fmt.Errorf("formatting %s: %v\n%s", name, err, code)
+	}
+	return ioutil.WriteFile(filepath.Join(g.output, name), formatted, 0644)
+}
+
+func (g *Generator) declare(name, path string) error {
+	if other, ok := g.types[name]; ok {
+		return fmt.Errorf("type %s is already declared by %s", name, other)
+	}
+	g.types[name] = path
+	return nil
+}
+
+func (g *Generator) generateMessage(path string, message *spec.MessageSpec) ([]byte, error) {
+	mg := &messageGenerator{
+		message:  message,
+		valid:    message.ValidVersions(),
+		flexible: message.FlexibleVersions().Intersect(message.ValidVersions()),
+	}
+
+	var body bytes.Buffer
+	mg.out = &body
+	if err := g.declare(message.Name, path); err != nil {
+		return nil, err
+	}
+	mg.printf("const (\n")
+	mg.printf("%sLowestSupportedVersion = int16(%d)\n", message.Name, mg.valid.Lowest)
+	mg.printf("%sHighestSupportedVersion = int16(%d)\n", message.Name, mg.valid.Highest)
+	mg.printf(")\n\n")
+	if err := mg.generateStruct(message.Name, message.Fields, true); err != nil {
+		return nil, err
+	}
+	for _, s := range message.Structs() {
+		if err := g.declare(s.Name, path); err != nil {
+			return nil, err
+		}
+		if err := mg.generateStruct(s.Name, s.Fields, false); err != nil {
+			return nil, fmt.Errorf("struct %s: %v", s.Name, err)
+		}
+	}
+
+	var code bytes.Buffer
+	code.WriteString(generatedHeader)
+	fmt.Fprintf(&code, "package %s\n\n", g.packageName)
+	code.WriteString("import (\n\t\"fmt\"\n")
+	if mg.usesSchema {
+		code.WriteString("\tkafkaschema \"kafka_schema/schema\"\n")
+	}
+	code.WriteString("\t\"kafka_schema/schema/buffer\"\n)\n\n")
+	code.Write(body.Bytes())
+	return code.Bytes(), nil
+}
+
+type messageGenerator struct {
+	message    *spec.MessageSpec
+	valid      spec.Versions
+	flexible   spec.Versions
+	out        *bytes.Buffer
+	usesSchema bool
+}
+
+func (mg *messageGenerator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(mg.out, format, args...)
+}
+
+// cond returns the go condition on the variable version testing that it is in versions
+func (mg *messageGenerator) cond(versions spec.Versions) string {
+	v := versions.Intersect(mg.valid)
+	if v.IsEmpty() {
+		return "false"
+	}
+	coversLowest := v.Lowest <= mg.valid.Lowest
+	coversHighest := v.Highest >= mg.valid.Highest
+	switch {
+	case coversLowest && coversHighest:
+		return "true"
+	case v.Lowest == v.Highest:
+		return fmt.Sprintf("version == %d", v.Lowest)
+	case coversLowest:
+		return fmt.Sprintf("version <= %d", v.Highest)
+	case coversHighest:
+		return fmt.Sprintf("version >= %d", v.Lowest)
+	default:
+		return fmt.Sprintf("version >= %d && version <= %d", v.Lowest, v.Highest)
+	}
+}
+
+func (mg *messageGenerator) isFlexible() bool {
+	return !mg.flexible.IsEmpty()
+}
+
+// regularVersions returns the versions where the field is not a tagged field
+func regularVersions(f *spec.FieldSpec) (spec.Versions, error) {
+	versions, tagged := f.Versions(), f.TaggedVersions()
+	switch {
+	case tagged.IsEmpty():
+		return versions, nil
+	case tagged.Lowest <= versions.Lowest && tagged.Highest >= versions.Highest:
+		return spec.NoVersions, nil
+	case tagged.Lowest > versions.Lowest && tagged.Highest >= versions.Highest:
+		return spec.Versions{Lowest: versions.Lowest, Highest: tagged.Lowest - 1}, nil
+	default:
+		return spec.NoVersions, fmt.Errorf("field %s: tagged versions %s must end the versions %s", f.Name, tagged, versions)
+	}
+}
+
+func goName(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+var scalarTypes = map[string]struct {
+	goType string
+	fn     string
+	size   int
+}{
+	"bool":    {"bool", "Bool", 1},
+	"int8":    {"int8", "Int8", 1},
+	"int16":   {"int16", "Int16", 2},
+	"uint16":  {"uint16", "Uint16", 2},
+	"int32":   {"int32", "Int32", 4},
+	"uint32":  {"uint32", "Uint32", 4},
+	"int64":   {"int64", "Int64", 8},
+	"float64": {"float64", "Float64", 8},
+	"uuid":    {"kafkaschema.Uuid", "Uuid", 16},
+}
+
+func isBytes(t string) bool {
+	return t == "bytes" || t == "records"
+}
+
+func (mg *messageGenerator) goType(f *spec.FieldSpec) (string, error) {
+	if f.IsArray() {
+		if f.IsStruct() {
+			return "[]" + f.ElementType(), nil
+		}
+		element, err := mg.primitiveGoType(f.ElementType(), false)
+		return "[]" + element, err
+	}
+	if f.IsStruct() {
+		if !f.NullableVersions().IsEmpty() {
+			return "", fmt.Errorf("field %s: nullable structs are not supported", f.Name)
+		}
+		return f.Type, nil
+	}
+	return mg.primitiveGoType(f.Type, !f.NullableVersions().IsEmpty())
+}
+
+func (mg *messageGenerator) primitiveGoType(t string, nullable bool) (string, error) {
+	if scalar, ok := scalarTypes[t]; ok {
+		if t == "uuid" {
+			mg.usesSchema = true
+		}
+		return scalar.goType, nil
+	}
+	switch {
+	case t == "string" && nullable:
+		return "*string", nil
+	case t == "string":
+		return "string", nil
+	case isBytes(t):
+		return "[]byte", nil
+	default:
+		return "", fmt.Errorf("unknown type %s", t)
+	}
+}
+
+// defaultValue returns the go expression of the default value of the field
+func (mg *messageGenerator) defaultValue(f *spec.FieldSpec) (string, error) {
+	goType, err := mg.goType(f)
+	if err != nil {
+		return "", err
+	}
+	nullable := !f.NullableVersions().IsEmpty()
+	if len(f.Default) == 0 {
+		switch {
+		case f.IsArray():
+			return goType + "{}", nil
+		case f.IsStruct():
+			return goType + "{}", nil
+		case f.Type == "string" && nullable:
+			return `stringPtr("")`, nil
+		case f.Type == "string":
+			return `""`, nil
+		case isBytes(f.Type):
+			return "[]byte{}", nil
+		case f.Type == "bool":
+			return "false", nil
+		case f.Type == "uuid":
+			return "kafkaschema.ZeroUuid", nil
+		default:
+			return "0", nil
+		}
+	}
+	value, err := spec.ParseDefault(f, nullable)
+	if err != nil {
+		return "", fmt.Errorf("field %s: %v", f.Name, err)
+	}
+	switch v := value.(type) {
+	case nil:
+		return "nil", nil
+	case string:
+		if nullable {
+			return fmt.Sprintf("stringPtr(%s)", strconv.Quote(v)), nil
+		}
+		return strconv.Quote(v), nil
+	case kafkaschema.Uuid:
+		return fmt.Sprintf("kafkaschema.Uuid(%#v)", [16]byte(v)), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+func isZeroValue(value string) bool {
+	return value == "0" || value == "false" || value == `""` || value == "nil" || value == "kafkaschema.ZeroUuid"
+}
+
+// nonDefault returns the go condition testing that the value differs from the default,
+// or an empty string if it cannot be tested
+func (mg *messageGenerator) nonDefault(f *spec.FieldSpec, value string) (string, error) {
+	def, err := mg.defaultValue(f)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case f.IsStruct() && !f.IsArray():
+		return "", nil
+	case f.IsArray() || isBytes(f.Type):
+		if def == "nil" {
+			return value + " != nil", nil
+		}
+		return fmt.Sprintf("len(%s) != 0", value), nil
+	case f.Type == "string" && !f.NullableVersions().IsEmpty():
+		if def == "nil" {
+			return value + " != nil", nil
+		}
+		inner := strings.TrimSuffix(strings.TrimPrefix(def, "stringPtr("), ")")
+		return fmt.Sprintf("(%s == nil || *%s != %s)", value, value, inner), nil
+	default:
+		return fmt.Sprintf("%s != %s", value, def), nil
+	}
+}
+
+func (mg *messageGenerator) usesFlexible(fields []*spec.FieldSpec) bool {
+	if mg.isFlexible() {
+		return true
+	}
+	for _, f := range fields {
+		if f.IsArray() || f.Type == "string" || isBytes(f.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+func (mg *messageGenerator) generateStruct(name string, fields []*spec.FieldSpec, topLevel bool) error {
+	if mg.isFlexible() {
+		mg.usesSchema = true
+	}
+	// type declaration
+	if topLevel {
+		mg.printf("// %s is the %s message, valid for versions %s\n", name, mg.message.Name, mg.valid)
+	}
+	mg.printf("type %s struct {\n", name)
+	for _, f := range fields {
+		goType, err := mg.goType(f)
+		if err != nil {
+			return err
+		}
+		if f.About != "" {
+			mg.printf("// %s\n", f.About)
+		}
+		mg.printf("%s %s\n", goName(f.Name), goType)
+	}
+	if mg.isFlexible() {
+		mg.printf("// UnknownTaggedFields are the tagged fields of the flexible versions unknown to this struct\n")
+		mg.printf("UnknownTaggedFields []kafkaschema.RawTaggedField\n")
+	}
+	mg.printf("}\n\n")
+
+	// constructor
+	mg.printf("// New%s returns a new %s whose fields are set to their default values\n", name, name)
+	mg.printf("func New%s() *%s {\n", name, name)
+	mg.printf("return &%s{\n", name)
+	for _, f := range fields {
+		def, err := mg.defaultValue(f)
+		if err != nil {
+			return err
+		}
+		if !isZeroValue(def) {
+			mg.printf("%s: %s,\n", goName(f.Name), def)
+		}
+	}
+	mg.printf("}\n}\n\n")
+
+	if err := mg.generateRead(name, fields, topLevel); err != nil {
+		return err
+	}
+	if err := mg.generateWrite(name, fields, topLevel); err != nil {
+		return err
+	}
+	return mg.generateSize(name, fields, topLevel)
+}
+
+func (mg *messageGenerator) versionCheck(name string, ret string) {
+	mg.printf("if version < %sLowestSupportedVersion || version > %sHighestSupportedVersion {\n", name, name)
+	mg.printf("return %sfmt.Errorf(\"unsupported %s version %%d\", version)\n", ret, name)
+	mg.printf("}\n")
+}
+
+func (mg *messageGenerator) openIf(cond string) bool {
+	if cond == "true" {
+		return false
+	}
+	mg.printf("if %s {\n", cond)
+	return true
+}
+
+func (mg *messageGenerator) knownTaggedFields(fields []*spec.FieldSpec) []*spec.FieldSpec {
+	tagged := make([]*spec.FieldSpec, 0)
+	for _, f := range fields {
+		if !f.TaggedVersions().Intersect(mg.flexible).IsEmpty() {
+			tagged = append(tagged, f)
+		}
+	}
+	return tagged
+}
+
+func (mg *messageGenerator) generateRead(name string, fields []*spec.FieldSpec, topLevel bool) error {
+	mg.printf("// Read reads the %s of the given version from the buffer\n", name)
+	mg.printf("func (m *%s) Read(buf *buffer.ByteBuffer, version int16) error {\n", name)
+	if topLevel {
+		mg.versionCheck(name, "")
+	}
+	mg.printf("var err error\n")
+	if mg.usesFlexible(fields) {
+		mg.printf("flexible := %s\n", mg.cond(mg.flexible))
+	}
+	for _, f := range fields {
+		regular, err := regularVersions(f)
+		if err != nil {
+			return err
+		}
+		def, err := mg.defaultValue(f)
+		if err != nil {
+			return err
+		}
+		cond := mg.cond(regular)
+		switch cond {
+		case "true":
+			if err = mg.readValue(f, "m."+goName(f.Name), "buf"); err != nil {
+				return err
+			}
+		case "false":
+			mg.printf("m.%s = %s\n", goName(f.Name), def)
+		default:
+			mg.printf("if %s {\n", cond)
+			if err = mg.readValue(f, "m."+goName(f.Name), "buf"); err != nil {
+				return err
+			}
+			mg.printf("} else {\nm.%s = %s\n}\n", goName(f.Name), def)
+		}
+	}
+	if mg.isFlexible() {
+		mg.printf("if flexible {\n")
+		mg.printf("taggedFields, err := readTaggedFields(buf)\n")
+		mg.printf("if err != nil {\nreturn err\n}\n")
+		tagged := mg.knownTaggedFields(fields)
+		if len(tagged) == 0 {
+			mg.printf("m.UnknownTaggedFields = taggedFields\n")
+		} else {
+			mg.printf("m.UnknownTaggedFields = nil\n")
+			mg.printf("for _, raw := range taggedFields {\nswitch {\n")
+			for _, f := range tagged {
+				if cond := mg.cond(f.TaggedVersions()); cond == "true" {
+					mg.printf("case raw.Tag == %d:\n", *f.Tag)
+				} else {
+					mg.printf("case raw.Tag == %d && %s:\n", *f.Tag, cond)
+				}
+				mg.printf("data := buffer.Wrap(raw.Data)\n")
+				if err := mg.readValue(f, "m."+goName(f.Name), "data"); err != nil {
+					return err
+				}
+			}
+			mg.printf("default:\nm.UnknownTaggedFields = append(m.UnknownTaggedFields, raw)\n}\n}\n")
+		}
+		mg.printf("}\n")
+	}
+	mg.printf("return err\n}\n\n")
+	return nil
+}
+
+func readError(f *spec.FieldSpec) string {
+	return fmt.Sprintf("return fmt.Errorf(\"error reading field '%s': %%v\", err)", f.SnakeCaseName())
+}
+
+func writeError(f *spec.FieldSpec) string {
+	return fmt.Sprintf("return fmt.Errorf(\"error writing field '%s': %%v\", err)", f.SnakeCaseName())
+}
+
+func (mg *messageGenerator) readValue(f *spec.FieldSpec, target, buf string) error {
+	nullable := mg.cond(f.NullableVersions())
+	switch {
+	case f.IsArray():
+		mg.printf("{\nvar n int\n")
+		mg.printf("if n, err = readArrayLength(%s, flexible, %s); err != nil {\n%s\n}\n", buf, nullable, readError(f))
+		goType, err := mg.goType(f)
+		if err != nil {
+			return err
+		}
+		mg.printf("if n < 0 {\n%s = nil\n} else {\n", target)
+		mg.printf("%s = make(%s, n)\n", target, goType)
+		mg.printf("for i := range %s {\n", target)
+		if f.IsStruct() {
+			mg.printf("if err = %s[i].Read(%s, version); err != nil {\n%s\n}\n", target, buf, readError(f))
+		} else if err = mg.readPrimitive(f, f.ElementType(), "false", target+"[i]", buf); err != nil {
+			return err
+		}
+		mg.printf("}\n}\n}\n")
+	case f.IsStruct():
+		mg.printf("if err = %s.Read(%s, version); err != nil {\n%s\n}\n", target, buf, readError(f))
+	default:
+		return mg.readPrimitive(f, f.Type, nullable, target, buf)
+	}
+	return nil
+}
+
+func (mg *messageGenerator) readPrimitive(f *spec.FieldSpec, t, nullable, target, buf string) error {
+	var call string
+	if scalar, ok := scalarTypes[t]; ok {
+		call = fmt.Sprintf("read%s(%s)", scalar.fn, buf)
+	} else if t == "string" && !f.NullableVersions().IsEmpty() && !f.IsArray() {
+		call = fmt.Sprintf("readNullableString(%s, flexible, %s)", buf, nullable)
+	} else if t == "string" {
+		call = fmt.Sprintf("readString(%s, flexible)", buf)
+	} else if t == "records" {
+		call = fmt.Sprintf("readBytes(%s, flexible, true)", buf)
+	} else if t == "bytes" {
+		call = fmt.Sprintf("readBytes(%s, flexible, %s)", buf, nullable)
+	} else {
+		return fmt.Errorf("field %s: unknown type %s", f.Name, t)
+	}
+	mg.printf("if %s, err = %s; err != nil {\n%s\n}\n", target, call, readError(f))
+	return nil
+}
+
+func (mg *messageGenerator) generateWrite(name string, fields []*spec.FieldSpec, topLevel bool) error {
+	mg.printf("// Write writes the %s with the given version into the buffer\n", name)
+	mg.printf("func (m *%s) Write(buf *buffer.ByteBuffer, version int16) error {\n", name)
+	if topLevel {
+		mg.versionCheck(name, "")
+	}
+	mg.printf("var err error\n")
+	if mg.usesFlexible(fields) {
+		mg.printf("flexible := %s\n", mg.cond(mg.flexible))
+	}
+	for _, f := range fields {
+		regular, err := regularVersions(f)
+		if err != nil {
+			return err
+		}
+		cond := mg.cond(regular)
+		value := "m." + goName(f.Name)
+		if cond == "true" {
+			if err = mg.writeValue(f, value, "buf"); err != nil {
+				return err
+			}
+			continue
+		}
+		check := ""
+		if !f.Ignorable && mg.cond(f.Versions()) != "true" {
+			if check, err = mg.nonDefault(f, value); err != nil {
+				return err
+			}
+		}
+		if cond != "false" {
+			mg.printf("if %s {\n", cond)
+			if err = mg.writeValue(f, value, "buf"); err != nil {
+				return err
+			}
+			if check != "" {
+				mg.printf("} else if !(%s) && %s {\n", mg.cond(f.Versions()), check)
+				mg.printf("return fmt.Errorf(\"attempted to write a non-default %s at version %%d\", version)\n", f.Name)
+			}
+			mg.printf("}\n")
+		} else if check != "" {
+			mg.printf("if !(%s) && %s {\n", mg.cond(f.Versions()), check)
+			mg.printf("return fmt.Errorf(\"attempted to write a non-default %s at version %%d\", version)\n}\n", f.Name)
+		}
+	}
+	if mg.isFlexible() {
+		tagged := mg.knownTaggedFields(fields)
+		mg.printf("if flexible {\n")
+		if len(tagged) == 0 {
+			mg.printf("taggedFields := make([]kafkaschema.RawTaggedField, 0, len(m.UnknownTaggedFields))\n")
+		} else {
+			mg.printf("taggedFields := make([]kafkaschema.RawTaggedField, 0, len(m.UnknownTaggedFields)+%d)\n", len(tagged))
+		}
+		for _, f := range tagged {
+			value := "m." + goName(f.Name)
+			check, err := mg.nonDefault(f, value)
+			if err != nil {
+				return err
+			}
+			if check == "" {
+				check = "true"
+			}
+			mg.printf("if %s && %s {\n", mg.cond(f.TaggedVersions()), check)
+			mg.printf("data := buffer.AllocateExpandable(16)\n")
+			if err = mg.writeValue(f, value, "data"); err != nil {
+				return err
+			}
+			mg.printf("data.Flip()\n")
+			mg.printf("taggedFields = append(taggedFields, kafkaschema.RawTaggedField{Tag: %d, Data: data.Bytes()})\n}\n", *f.Tag)
+		}
+		mg.printf("taggedFields = append(taggedFields, m.UnknownTaggedFields...)\n")
+		mg.printf("if err = writeTaggedFields(buf, taggedFields); err != nil {\nreturn err\n}\n")
+		mg.printf("}\n")
+	}
+	mg.printf("return err\n}\n\n")
+	return nil
+}
+
+func (mg *messageGenerator) writeValue(f *spec.FieldSpec, value, buf string) error {
+	nullable := mg.cond(f.NullableVersions())
+	switch {
+	case f.IsArray():
+		mg.printf("if err = writeArrayLength(%s, len(%s), %s == nil, flexible, %s); err != nil {\n%s\n}\n", buf, value, value, nullable, writeError(f))
+		mg.printf("for i := range %s {\n", value)
+		if f.IsStruct() {
+			mg.printf("if err = %s[i].Write(%s, version); err != nil {\n%s\n}\n", value, buf, writeError(f))
+		} else if err := mg.writePrimitive(f, f.ElementType(), "false", value+"[i]", buf); err != nil {
+			return err
+		}
+		mg.printf("}\n")
+	case f.IsStruct():
+		mg.printf("if err = %s.Write(%s, version); err != nil {\n%s\n}\n", value, buf, writeError(f))
+	default:
+		return mg.writePrimitive(f, f.Type, nullable, value, buf)
+	}
+	return nil
+}
+
+func (mg *messageGenerator) writePrimitive(f *spec.FieldSpec, t, nullable, value, buf string) error {
+	var call string
+	if scalar, ok := scalarTypes[t]; ok {
+		call = fmt.Sprintf("write%s(%s, %s)", scalar.fn, buf, value)
+	} else if t == "string" && !f.NullableVersions().IsEmpty() && !f.IsArray() {
+		call = fmt.Sprintf("writeNullableString(%s, %s, flexible, %s)", buf, value, nullable)
+	} else if t == "string" {
+		call = fmt.Sprintf("writeString(%s, %s, flexible)", buf, value)
+	} else if t == "records" {
+		call = fmt.Sprintf("writeBytes(%s, %s, flexible, true)", buf, value)
+	} else if t == "bytes" {
+		call = fmt.Sprintf("writeBytes(%s, %s, flexible, %s)", buf, value, nullable)
+	} else {
+		return fmt.Errorf("field %s: unknown type %s", f.Name, t)
+	}
+	mg.printf("if err = %s; err != nil {\n%s\n}\n", call, writeError(f))
+	return nil
+}
+
+func (mg *messageGenerator) generateSize(name string, fields []*spec.FieldSpec, topLevel bool) error {
+	mg.printf("// Size returns the size in bytes of the %s written with the given version\n", name)
+	mg.printf("func (m *%s) Size(version int16) (int, error) {\n", name)
+	if topLevel {
+		mg.versionCheck(name, "0, ")
+	}
+	mg.printf("size := 0\n")
+	if mg.usesFlexible(fields) {
+		mg.printf("flexible := %s\n", mg.cond(mg.flexible))
+	}
+	for _, f := range fields {
+		regular, err := regularVersions(f)
+		if err != nil {
+			return err
+		}
+		cond := mg.cond(regular)
+		if cond == "false" {
+			continue
+		}
+		opened := mg.openIf(cond)
+		if err = mg.sizeValue(f, "m."+goName(f.Name), "size"); err != nil {
+			return err
+		}
+		if opened {
+			mg.printf("}\n")
+		}
+	}
+	if mg.isFlexible() {
+		mg.printf("if flexible {\n")
+		mg.printf("numTaggedFields := len(m.UnknownTaggedFields)\n")
+		mg.printf("for _, raw := range m.UnknownTaggedFields {\nsize += sizeOfTaggedField(raw.Tag, len(raw.Data))\n}\n")
+		for _, f := range mg.knownTaggedFields(fields) {
+			value := "m." + goName(f.Name)
+			check, err := mg.nonDefault(f, value)
+			if err != nil {
+				return err
+			}
+			if check == "" {
+				check = "true"
+			}
+			mg.printf("if %s && %s {\n", mg.cond(f.TaggedVersions()), check)
+			mg.printf("fieldSize := 0\n")
+			if err = mg.sizeValue(f, value, "fieldSize"); err != nil {
+				return err
+			}
+			mg.printf("numTaggedFields++\n")
+			mg.printf("size += sizeOfTaggedField(%d, fieldSize)\n}\n", *f.Tag)
+		}
+		mg.printf("size += buffer.SizeOfUnsignedVarint(int32(numTaggedFields))\n")
+		mg.printf("}\n")
+	}
+	mg.printf("return size, nil\n}\n\n")
+	return nil
+}
+
+func (mg *messageGenerator) sizeValue(f *spec.FieldSpec, value, acc string) error {
+	switch {
+	case f.IsArray():
+		mg.printf("%s += sizeOfArrayLength(len(%s), flexible)\n", acc, value)
+		if f.IsStruct() {
+			mg.printf("for i := range %s {\n", value)
+			mg.printf("elementSize, err := %s[i].Size(version)\n", value)
+			mg.printf("if err != nil {\nreturn 0, err\n}\n")
+			mg.printf("%s += elementSize\n}\n", acc)
+		} else if scalar, ok := scalarTypes[f.ElementType()]; ok {
+			mg.printf("%s += len(%s) * %d\n", acc, value, scalar.size)
+		} else {
+			mg.printf("for i := range %s {\n", value)
+			if err := mg.sizePrimitive(f, f.ElementType(), value+"[i]", acc); err != nil {
+				return err
+			}
+			mg.printf("}\n")
+		}
+	case f.IsStruct():
+		mg.printf("{\nstructSize, err := %s.Size(version)\n", value)
+		mg.printf("if err != nil {\nreturn 0, err\n}\n")
+		mg.printf("%s += structSize\n}\n", acc)
+	default:
+		return mg.sizePrimitive(f, f.Type, value, acc)
+	}
+	return nil
+}
+
+func (mg *messageGenerator) sizePrimitive(f *spec.FieldSpec, t, value, acc string) error {
+	if scalar, ok := scalarTypes[t]; ok {
+		mg.printf("%s += %d\n", acc, scalar.size)
+	} else if t == "string" && !f.NullableVersions().IsEmpty() && !f.IsArray() {
+		mg.printf("%s += sizeOfNullableString(%s, flexible)\n", acc, value)
+	} else if t == "string" {
+		mg.printf("%s += sizeOfString(%s, flexible)\n", acc, value)
+	} else if isBytes(t) {
+		mg.printf("%s += sizeOfBytes(%s, flexible)\n", acc, value)
+	} else {
+		return fmt.Errorf("field %s: unknown type %s", f.Name, t)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateIsUpToDate(t *testing.T) {
+	dir := filepath.Join("..", "..", "deserialize", "message")
+	names := []string{"OffsetCommitKey", "OffsetCommitValue", "GroupMetadataKey", "GroupMetadataValue"}
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dir, name+".json")
+	}
+	output := t.TempDir()
+	if err := NewGenerator("message", output).Generate(paths...); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range append(names, "MessageUtil") {
+		got, err := ioutil.ReadFile(filepath.Join(output, name+".go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := ioutil.ReadFile(filepath.Join(dir, name+".go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s.go is out of date, run go generate in %s", name, dir)
+		}
+	}
+}
+
+func TestGenerateKnownTaggedFields(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Tagged.json")
+	specification := `{
+  "type": "data",
+  "name": "Tagged",
+  "validVersions": "0-1",
+  "flexibleVersions": "1+",
+  "fields": [
+    { "name": "id", "type": "string", "versions": "0+", "about": "The id." },
+    { "name": "epoch", "type": "int32", "versions": "1+", "default": -1, "taggedVersions": "1+", "tag": 3,
+      "about": "The epoch." }
+  ]
+}`
+	if err := ioutil.WriteFile(path, []byte(specification), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewGenerator("tagged", dir).Generate(path); err != nil {
+		t.Fatal(err)
+	}
+	code, err := ioutil.ReadFile(filepath.Join(dir, "Tagged.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"case raw.Tag == 3 && version == 1:",
+		"kafkaschema.RawTaggedField{Tag: 3, Data: data.Bytes()}",
+		"size += sizeOfTaggedField(3, fieldSize)",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Tagged.go does not contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+package main
+
+// messageUtil is the source of the helpers shared by the generated messages, the
+// package name is its only format argument
+const messageUtil = `// Code generated by messagegen. DO NOT EDIT.
+
+package %s
+
+import (
+	"fmt"
+	kafkaschema "kafka_schema/schema"
+	"kafka_schema/schema/buffer"
+	"math"
+	"sort"
+)
+
+func stringPtr(str string) *string {
+	return &str
+}
+
+func readBool(buf *buffer.ByteBuffer) (bool, error) {
+	b, err := buf.Get()
+	return b != 0, err
+}
+
+func writeBool(buf *buffer.ByteBuffer, v bool) error {
+	if v {
+		return buf.PutByte(1)
+	}
+	return buf.PutByte(0)
+}
+
+func readInt8(buf *buffer.ByteBuffer) (int8, error) {
+	return buf.GetInt8()
+}
+
+func writeInt8(buf *buffer.ByteBuffer, v int8) error {
+	return buf.PutInt8(v)
+}
+
+func readInt16(buf *buffer.ByteBuffer) (int16, error) {
+	return buf.GetInt16()
+}
+
+func writeInt16(buf *buffer.ByteBuffer, v int16) error {
+	return buf.PutInt16(v)
+}
+
+func readUint16(buf *buffer.ByteBuffer) (uint16, error) {
+	v, err := buf.GetInt16()
+	return uint16(v), err
+}
+
+func writeUint16(buf *buffer.ByteBuffer, v uint16) error {
+	return buf.PutInt16(int16(v))
+}
+
+func readInt32(buf *buffer.ByteBuffer) (int32, error) {
+	return buf.GetInt32()
+}
+
+func writeInt32(buf *buffer.ByteBuffer, v int32) error {
+	return buf.PutInt32(v)
+}
+
+func readUint32(buf *buffer.ByteBuffer) (uint32, error) {
+	v, err := buf.GetInt32()
+	return uint32(v), err
+}
+
+func writeUint32(buf *buffer.ByteBuffer, v uint32) error {
+	return buf.PutInt32(int32(v))
+}
+
+func readInt64(buf *buffer.ByteBuffer) (int64, error) {
+	return buf.GetInt64()
+}
+
+func writeInt64(buf *buffer.ByteBuffer, v int64) error {
+	return buf.PutInt64(v)
+}
+
+func readFloat64(buf *buffer.ByteBuffer) (float64, error) {
+	return buf.GetFloat64()
+}
+
+func writeFloat64(buf *buffer.ByteBuffer, v float64) error {
+	return buf.PutFloat64(v)
+}
+
+func readUuid(buf *buffer.ByteBuffer) (kafkaschema.Uuid, error) {
+	var uuid kafkaschema.Uuid
+	for i := range uuid {
+		b, err := buf.Get()
+		if err != nil {
+			return uuid, err
+		}
+		uuid[i] = b
+	}
+	return uuid, nil
+}
+
+func writeUuid(buf *buffer.ByteBuffer, v kafkaschema.Uuid) error {
+	return buf.PutBytes(v[:])
+}
+
+// readLength reads a length prefix, -1 stands for null
+func readLength(buf *buffer.ByteBuffer, flexible bool, shortLength bool) (int, error) {
+	if flexible {
+		length, err := buf.GetUnsignedVarint()
+		return int(length) - 1, err
+	}
+	if shortLength {
+		length, err := buf.GetInt16()
+		return int(length), err
+	}
+	length, err := buf.GetInt32()
+	return int(length), err
+}
+
+func readNullableString(buf *buffer.ByteBuffer, flexible bool, nullable bool) (*string, error) {
+	length, err := readLength(buf, flexible, true)
+	if err != nil {
+		return nil, err
+	}
+	if length < 0 {
+		if !nullable {
+			return nil, fmt.Errorf("non-nullable string is null")
+		}
+		return nil, nil
+	}
+	if length > buf.Remaining() {
+		return nil, fmt.Errorf("error reading string of length %%d, only %%d bytes available", length, buf.Remaining())
+	}
+	str, err := buf.GetString(0, length)
+	if err != nil {
+		return nil, err
+	}
+	return &str, buf.SetPosition(buf.GetPosition() + length)
+}
+
+func readString(buf *buffer.ByteBuffer, flexible bool) (string, error) {
+	str, err := readNullableString(buf, flexible, false)
+	if err != nil {
+		return "", err
+	}
+	return *str, nil
+}
+
+func writeNullableString(buf *buffer.ByteBuffer, v *string, flexible bool, nullable bool) error {
+	if v == nil {
+		if !nullable {
+			return fmt.Errorf("non-nullable string is null")
+		}
+		if flexible {
+			return buf.PutUnsignedVarint(0)
+		}
+		return buf.PutInt16(-1)
+	}
+	return writeString(buf, *v, flexible)
+}
+
+func writeString(buf *buffer.ByteBuffer, v string, flexible bool) error {
+	if len(v) > math.MaxInt16 {
+		return fmt.Errorf("string length %%d is larger than the maximum string length", len(v))
+	}
+	var err error
+	if flexible {
+		err = buf.PutUnsignedVarint(int32(len(v) + 1))
+	} else {
+		err = buf.PutInt16(int16(len(v)))
+	}
+	if err != nil {
+		return err
+	}
+	return buf.PutString(v)
+}
+
+func sizeOfNullableString(v *string, flexible bool) int {
+	if v == nil {
+		return sizeOfString("", flexible)
+	}
+	return sizeOfString(*v, flexible)
+}
+
+func sizeOfString(v string, flexible bool) int {
+	if flexible {
+		return buffer.SizeOfUnsignedVarint(int32(len(v)+1)) + len(v)
+	}
+	return 2 + len(v)
+}
+
+func readBytes(buf *buffer.ByteBuffer, flexible bool, nullable bool) ([]byte, error) {
+	length, err := readLength(buf, flexible, false)
+	if err != nil {
+		return nil, err
+	}
+	if length < 0 {
+		if !nullable {
+			return nil, fmt.Errorf("non-nullable bytes are null")
+		}
+		return nil, nil
+	}
+	if length > buf.Remaining() {
+		return nil, fmt.Errorf("error reading bytes of size %%d, only %%d bytes available", length, buf.Remaining())
+	}
+	bs := make([]byte, length)
+	for i := range bs {
+		if bs[i], err = buf.Get(); err != nil {
+			return nil, err
+		}
+	}
+	return bs, nil
+}
+
+func writeBytes(buf *buffer.ByteBuffer, v []byte, flexible bool, nullable bool) error {
+	if err := writeArrayLength(buf, len(v), v == nil, flexible, nullable); err != nil {
+		return err
+	}
+	return buf.PutBytes(v)
+}
+
+func sizeOfBytes(v []byte, flexible bool) int {
+	return sizeOfArrayLength(len(v), flexible) + len(v)
+}
+
+// readArrayLength reads the length of an array, -1 stands for null
+func readArrayLength(buf *buffer.ByteBuffer, flexible bool, nullable bool) (int, error) {
+	length, err := readLength(buf, flexible, false)
+	if err != nil {
+		return 0, err
+	}
+	if length < 0 {
+		if !nullable {
+			return 0, fmt.Errorf("non-nullable array is null")
+		}
+		return -1, nil
+	}
+	if length > buf.Remaining() {
+		return 0, fmt.Errorf("error reading array of size %%d, only %%d bytes available", length, buf.Remaining())
+	}
+	return length, nil
+}
+
+// writeArrayLength writes the length prefix of an array or bytes, a non-nullable null is written empty
+func writeArrayLength(buf *buffer.ByteBuffer, length int, null bool, flexible bool, nullable bool) error {
+	if null && nullable {
+		length = -1
+	}
+	if flexible {
+		return buf.PutUnsignedVarint(int32(length + 1))
+	}
+	return buf.PutInt32(int32(length))
+}
+
+func sizeOfArrayLength(length int, flexible bool) int {
+	if flexible {
+		return buffer.SizeOfUnsignedVarint(int32(length + 1))
+	}
+	return 4
+}
+
+func readTaggedFields(buf *buffer.ByteBuffer) ([]kafkaschema.RawTaggedField, error) {
+	numTaggedFields, err := buf.GetUnsignedVarint()
+	if err != nil {
+		return nil, fmt.Errorf("error reading tagged fields: %%v", err)
+	}
+	if numTaggedFields < 0 || int(numTaggedFields) > buf.Remaining() {
+		return nil, fmt.Errorf("error reading %%d tagged fields, only %%d bytes available", numTaggedFields, buf.Remaining())
+	}
+	if numTaggedFields == 0 {
+		return nil, nil
+	}
+	taggedFields := make([]kafkaschema.RawTaggedField, 0, numTaggedFields)
+	prevTag := -1
+	for i := 0; i < int(numTaggedFields); i++ {
+		tag, err := buf.GetUnsignedVarint()
+		if err != nil {
+			return nil, fmt.Errorf("error reading tagged fields: %%v", err)
+		}
+		if int(tag) <= prevTag {
+			return nil, fmt.Errorf("invalid or out-of-order tag %%d", tag)
+		}
+		prevTag = int(tag)
+		size, err := buf.GetUnsignedVarint()
+		if err != nil {
+			return nil, fmt.Errorf("error reading tagged field %%d: %%v", tag, err)
+		}
+		if size < 0 || int(size) > buf.Remaining() {
+			return nil, fmt.Errorf("error reading tagged field %%d of size %%d, only %%d bytes available", tag, size, buf.Remaining())
+		}
+		data := make([]byte, size)
+		for i := range data {
+			if data[i], err = buf.Get(); err != nil {
+				return nil, err
+			}
+		}
+		taggedFields = append(taggedFields, kafkaschema.RawTaggedField{Tag: int(tag), Data: data})
+	}
+	return taggedFields, nil
+}
+
+func writeTaggedFields(buf *buffer.ByteBuffer, taggedFields []kafkaschema.RawTaggedField) error {
+	sort.SliceStable(taggedFields, func(i, j int) bool {
+		return taggedFields[i].Tag < taggedFields[j].Tag
+	})
+	if err := buf.PutUnsignedVarint(int32(len(taggedFields))); err != nil {
+		return err
+	}
+	for i, taggedField := range taggedFields {
+		if i > 0 && taggedFields[i-1].Tag == taggedField.Tag {
+			return fmt.Errorf("tag %%d is written twice", taggedField.Tag)
+		}
+		if err := buf.PutUnsignedVarint(int32(taggedField.Tag)); err != nil {
+			return err
+		}
+		if err := buf.PutUnsignedVarint(int32(len(taggedField.Data))); err != nil {
+			return err
+		}
+		if err := buf.PutBytes(taggedField.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sizeOfTaggedField(tag int, size int) int {
+	return buffer.SizeOfUnsignedVarint(int32(tag)) + buffer.SizeOfUnsignedVarint(int32(size)) + size
+}
+`
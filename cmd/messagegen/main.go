@@ -0,0 +1,30 @@
+// Command messagegen generates typed Go structs from Kafka JSON message specifications.
+//
+// Usage:
+//
+//	messagegen [-package name] [-output dir] spec.json...
+//
+// It is meant to be run by go:generate from the package receiving the generated code.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	packageName := flag.String("package", "message", "the name of the package of the generated code")
+	output := flag.String("output", ".", "the directory the generated files are written to")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: messagegen [-package name] [-output dir] spec.json...")
+		os.Exit(2)
+	}
+
+	if err := NewGenerator(*packageName, *output).Generate(flag.Args()...); err != nil {
+		fmt.Fprintf(os.Stderr, "messagegen: %v\n", err)
+		os.Exit(1)
+	}
+}
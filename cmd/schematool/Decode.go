@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"kafka_schema/schema"
+	"kafka_schema/schema/buffer"
+	"os"
+	"strings"
+)
+
+// decode reads the data with a schema written in the schema DSL and prints it as JSON
+func decode(args []string) error {
+	flags := flag.NewFlagSet("decode", flag.ExitOnError)
+	dsl := flags.String("schema", "", "the schema of the data in the schema DSL, e.g. \"group:STRING partition:INT32\"")
+	encoding := flags.String("encoding", "hex", "the encoding of the data, hex or base64")
+	opts := &kafkaschema.DecodeOptions{}
+	flags.IntVar(&opts.MaxArrayLength, "max-array-length", 0, "the maximum number of elements of an array, 0 for no limit")
+	flags.IntVar(&opts.MaxBytesLength, "max-bytes-length", 0, "the maximum length of a string or bytes value, 0 for no limit")
+	flags.IntVar(&opts.MaxDepth, "max-depth", 0, "the maximum nesting depth of structs, 0 for no limit")
+	flags.IntVar(&opts.MaxAllocation, "max-allocation", 0, "the maximum number of bytes allocated for the values, 0 for no limit")
+	_ = flags.Parse(args)
+
+	if *dsl == "" {
+		return fmt.Errorf("decode requires a -schema")
+	}
+	schema, err := kafkaschema.ParseSchema(*dsl)
+	if err != nil {
+		return err
+	}
+
+	var text string
+	if flags.NArg() > 0 {
+		text = flags.Arg(0)
+	} else {
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		text = string(data)
+	}
+	text = strings.TrimSpace(text)
+
+	var data []byte
+	switch *encoding {
+	case "hex":
+		data, err = hex.DecodeString(text)
+	case "base64":
+		data, err = base64.StdEncoding.DecodeString(text)
+	default:
+		return fmt.Errorf("unknown encoding %s", *encoding)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s data: %v", *encoding, err)
+	}
+
+	buf := buffer.NewByteBuffer(data)
+	o, err := schema.Read(buf, opts)
+	if err != nil {
+		return err
+	}
+	if buf.Remaining() > 0 {
+		return fmt.Errorf("%d bytes remain after the data", buf.Remaining())
+	}
+	out, err := o.(*kafkaschema.Struct).MarshalJSON()
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
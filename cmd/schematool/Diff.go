@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"kafka_schema/deserialize"
+	"kafka_schema/schema"
+	"os"
+)
+
+// diff compares every registered schema with its previous version, or only the schemas
+// with the given names
+func diff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	_ = flags.Parse(args)
+	names := make(map[string]bool)
+	for _, name := range flags.Args() {
+		names[name] = true
+	}
+
+	schemas, err := deserialize.RegisteredSchemas()
+	if err != nil {
+		return err
+	}
+	previous := make(map[string]deserialize.NamedSchema)
+	for _, s := range schemas {
+		if s.Version < 0 || len(names) > 0 && !names[s.Name] {
+			continue
+		}
+		if prev, ok := previous[s.Name]; ok {
+			printDiff(os.Stdout, prev, s)
+		}
+		previous[s.Name] = s
+	}
+	return nil
+}
+
+func printDiff(w io.Writer, old, updated deserialize.NamedSchema) {
+	d := kafkaschema.Diff(old.Schema, updated.Schema)
+	fmt.Fprintf(w, "%s -> V%d: %s\n", old.Title(), updated.Version, d.Compatibility())
+	for _, c := range d.Changes {
+		fmt.Fprintf(w, "  %s\n", c)
+	}
+}
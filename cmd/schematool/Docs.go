@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"kafka_schema/deserialize"
+	"kafka_schema/schema/doc"
+	"os"
+)
+
+func docs(args []string) error {
+	flags := flag.NewFlagSet("docs", flag.ExitOnError)
+	format := flags.String("format", "markdown", "the output format, markdown or html")
+	output := flags.String("output", "", "the file the documentation is written to, the standard output by default")
+	_ = flags.Parse(args)
+
+	var render func(io.Writer, ...doc.Section) error
+	switch *format {
+	case "markdown":
+		render = doc.Markdown
+	case "html":
+		render = doc.HTML
+	default:
+		return fmt.Errorf("unknown format %s", *format)
+	}
+
+	schemas, err := deserialize.RegisteredSchemas()
+	if err != nil {
+		return err
+	}
+	sections := make([]doc.Section, len(schemas))
+	for i, s := range schemas {
+		sections[i] = doc.Section{Title: s.Title(), Schema: s.Schema}
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return render(w, sections...)
+}
@@ -0,0 +1,44 @@
+// Command schematool works with the schemas of the __consumer_offsets records.
+//
+// Usage:
+//
+//	schematool docs [-format markdown|html] [-output file]
+//	schematool diff [name...]
+//	schematool decode -schema dsl [-encoding hex|base64] [data]
+//
+// The docs command renders the tables of the Kafka protocol guide for every registered schema.
+// The diff command compares every version of the registered schemas, or of the named ones,
+// with the previous version and reports the changed fields and their compatibility.
+// The decode command prints as JSON the data, given as argument or on the standard input,
+// read with a schema written in the schema DSL of kafkaschema.ParseSchema.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = "usage: schematool docs [-format markdown|html] [-output file] | diff [name...] | decode -schema dsl [-encoding hex|base64] [data]"
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "docs":
+		err = docs(os.Args[2:])
+	case "diff":
+		err = diff(os.Args[2:])
+	case "decode":
+		err = decode(os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "schematool: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -8,6 +8,7 @@ import (
 type Assignment struct {
 	partitions []*common.TopicPartition
 	userData   *buffer2.ByteBuffer
+	warnings   []error
 }
 
 func NewAssignment(partitions []*common.TopicPartition, userData *buffer2.ByteBuffer) *Assignment {
@@ -20,3 +21,8 @@ func NewAssignment(partitions []*common.TopicPartition, userData *buffer2.ByteBu
 func (a *Assignment) Partitions() []*common.TopicPartition {
 	return a.partitions
 }
+
+// Warnings returns the problems a lenient decoding of the assignment kept going past
+func (a *Assignment) Warnings() []error {
+	return a.warnings
+}
@@ -5,7 +5,6 @@ import (
 	"kafka_schema/deserialize/common"
 	"kafka_schema/schema"
 	buffer2 "kafka_schema/schema/buffer"
-	"kafka_schema/util"
 )
 
 const (
@@ -17,8 +16,13 @@ const (
 	UserDataKeyName        = "user_data"
 	ConsumerProtocolV0     = int16(0)
 	ConsumerProtocolV1     = int16(1)
+	SubscriptionName       = "ConsumerProtocolSubscription"
+	AssignmentName         = "ConsumerProtocolAssignment"
 )
 
+// The schemas of the consumer protocol, they are set by InitConsumerProtocol.
+//
+// Deprecated: the schemas of a Decoder are its own, see Decoder.RegisteredSchemas.
 var (
 	ConsumerProtocolHeaderSchema *kafkaschema.Schema
 	AssignmentV0                 *kafkaschema.Schema
@@ -27,150 +31,228 @@ var (
 	TopicAssignmentV0            *kafkaschema.Schema
 )
 
-func InitConsumerProtocol() (err error) {
-	if ConsumerProtocolHeaderSchema, err = kafkaschema.NewSchema(
+// InitConsumerProtocol sets the schema variables of the consumer protocol.
+//
+// Deprecated: use NewDecoder, the consumer protocol of a Decoder needs no initialization.
+func InitConsumerProtocol() error {
+	schemas, err := newConsumerProtocolSchemas()
+	if err != nil {
+		return err
+	}
+	schemas.setGlobals()
+	return nil
+}
+
+// consumerProtocolSchemas holds the schemas a consumer protocol reads with
+type consumerProtocolSchemas struct {
+	header            *kafkaschema.Schema
+	topicAssignmentV0 *kafkaschema.Schema
+	assignmentV0      *kafkaschema.Schema
+	subscriptionV0    *kafkaschema.Schema
+	subscriptionV1    *kafkaschema.Schema
+}
+
+func newConsumerProtocolSchemas() (schemas *consumerProtocolSchemas, err error) {
+	schemas = &consumerProtocolSchemas{}
+	if schemas.header, err = kafkaschema.NewSchema(
 		kafkaschema.NewField(VersionKeyName, kafkaschema.INT16),
 	); err != nil {
-		return fmt.Errorf("initConsumerProtocol %s", err)
+		return nil, fmt.Errorf("initConsumerProtocol %s", err)
 	}
 
-	if TopicAssignmentV0, err = kafkaschema.NewSchema(
+	if schemas.topicAssignmentV0, err = kafkaschema.NewSchema(
 		kafkaschema.NewField(TopicKeyName, kafkaschema.STRING),
 		kafkaschema.NewField(PartitionsKeyName, kafkaschema.NewArrayOf(kafkaschema.INT32)),
 	); err != nil {
-		return fmt.Errorf("initConsumerProtocol %s", err)
+		return nil, fmt.Errorf("initConsumerProtocol %s", err)
 	}
 
-	if AssignmentV0, err = kafkaschema.NewSchema(
-		kafkaschema.NewField(TopicPartitionsKeyName, kafkaschema.NewArrayOf(TopicAssignmentV0)),
+	if schemas.assignmentV0, err = kafkaschema.NewSchema(
+		kafkaschema.NewField(TopicPartitionsKeyName, kafkaschema.NewArrayOf(schemas.topicAssignmentV0)),
 		kafkaschema.NewField(UserDataKeyName, kafkaschema.NullableBytes),
 	); err != nil {
-		return fmt.Errorf("initConsumerProtocol %s", err)
+		return nil, fmt.Errorf("initConsumerProtocol %s", err)
 	}
 
-	if SubscriptionV0, err = kafkaschema.NewSchema(
+	if schemas.subscriptionV0, err = kafkaschema.NewSchema(
 		kafkaschema.NewField(TopicKeyName, kafkaschema.NewArrayOf(kafkaschema.STRING)),
 		kafkaschema.NewField(UserDataKeyName, kafkaschema.NullableBytes),
 	); err != nil {
-		return fmt.Errorf("initConsumerProtocol %s", err)
+		return nil, fmt.Errorf("initConsumerProtocol %s", err)
 	}
 
-	if SubscriptionV1, err = kafkaschema.NewSchema(
+	if schemas.subscriptionV1, err = kafkaschema.NewSchema(
 		kafkaschema.NewField(TopicKeyName, kafkaschema.NewArrayOf(kafkaschema.STRING)),
 		kafkaschema.NewField(UserDataKeyName, kafkaschema.NullableBytes),
-		kafkaschema.NewField(OwnedPartitionsKeyName, kafkaschema.NewArrayOf(TopicAssignmentV0)),
+		kafkaschema.NewField(OwnedPartitionsKeyName, kafkaschema.NewArrayOf(schemas.topicAssignmentV0)),
 	); err != nil {
-		return fmt.Errorf("initConsumerProtocol %s", err)
+		return nil, fmt.Errorf("initConsumerProtocol %s", err)
 	}
+	return schemas, nil
+}
 
-	return
+func (schemas *consumerProtocolSchemas) setGlobals() {
+	ConsumerProtocolHeaderSchema = schemas.header
+	TopicAssignmentV0 = schemas.topicAssignmentV0
+	AssignmentV0 = schemas.assignmentV0
+	SubscriptionV0 = schemas.subscriptionV0
+	SubscriptionV1 = schemas.subscriptionV1
 }
 
-type consumerProtocol struct{}
+type consumerProtocol struct {
+	schemas *consumerProtocolSchemas
+	lenient bool
+	opts    *kafkaschema.DecodeOptions
+}
 
+// ConsumerProtocol returns a consumer protocol reading with the schemas set by InitConsumerProtocol.
+//
+// Deprecated: use Decoder.ConsumerProtocol.
 func ConsumerProtocol() *consumerProtocol {
-	return &consumerProtocol{}
+	return &consumerProtocol{schemas: &consumerProtocolSchemas{
+		header:            ConsumerProtocolHeaderSchema,
+		topicAssignmentV0: TopicAssignmentV0,
+		assignmentV0:      AssignmentV0,
+		subscriptionV0:    SubscriptionV0,
+		subscriptionV1:    SubscriptionV1,
+	}}
+}
+
+// WithLenient returns a consumer protocol decoding leniently or not. A lenient consumer
+// protocol reports trailing bytes and versions newer than the known ones, which are decoded
+// with the latest known schema, as warnings of the result.
+func (cp *consumerProtocol) WithLenient(lenient bool) *consumerProtocol {
+	protocol := *cp
+	protocol.lenient = lenient
+	return &protocol
+}
+
+// WithDecodeOptions returns a consumer protocol reading within the limits of the options
+func (cp *consumerProtocol) WithDecodeOptions(opts *kafkaschema.DecodeOptions) *consumerProtocol {
+	protocol := *cp
+	protocol.opts = opts
+	return &protocol
+}
+
+// warnings returns the warnings of a decoding which started at the offset and ended at the
+// position of the buffer, the version is the version read
+func (cp *consumerProtocol) warnings(buffer *buffer2.ByteBuffer, name string, version int16, offset int) []error {
+	if !cp.lenient {
+		return nil
+	}
+	warnings := make([]error, 0)
+	if version > ConsumerProtocolV1 {
+		warning := fmt.Errorf("%w: %d, decoded with version %d", kafkaschema.ErrUnknownVersion, version, ConsumerProtocolV1)
+		warnings = append(warnings, kafkaschema.LocateDecodeError(warning, VersionKeyName, offset))
+	}
+	if buffer.HasRemaining() {
+		warning := fmt.Errorf("%w: %d bytes after the %s", kafkaschema.ErrTrailingBytes, buffer.Remaining(), name)
+		warnings = append(warnings, kafkaschema.LocateDecodeError(warning, "", buffer.AbsolutePosition()))
+	}
+	for i, warning := range warnings {
+		warnings[i] = kafkaschema.NameDecodeError(warning, name, int(version), offset)
+	}
+	return warnings
 }
 
 func (cp *consumerProtocol) deserializeVersion(buffer *buffer2.ByteBuffer) (int16, error) {
-	header, err := ConsumerProtocolHeaderSchema.Read(buffer)
+	header, err := cp.schemas.header.Read(buffer, cp.opts)
 	if err != nil {
-		return 0, fmt.Errorf("deserialize version field: %s", err)
+		return 0, err
 	}
 
 	if headerStruct, ok := header.(*kafkaschema.Struct); ok {
 		return headerStruct.GetInt16(VersionKeyName)
 	} else {
-		return 0, fmt.Errorf("deserialize version field")
+		return 0, fmt.Errorf("%w: consumer protocol header is a %T", kafkaschema.ErrTypeMismatch, header)
 	}
 }
 
 func (cp *consumerProtocol) deserializeSubscriptionV0(buffer *buffer2.ByteBuffer) (*Subscription, error) {
-	subscriptionV0, err := SubscriptionV0.Read(buffer)
+	subscriptionV0, err := cp.schemas.subscriptionV0.Read(buffer, cp.opts)
 	if err != nil {
 		return nil, err
 	}
 
 	if Struct, ok := subscriptionV0.(*kafkaschema.Struct); ok {
 		userData, err := Struct.GetByteBuffer(UserDataKeyName)
+		if err != nil {
+			return nil, err
+		}
 		topics, err := cp.deserializeTopics(Struct)
 		if err != nil {
 			return nil, err
 		}
 		return NewSubscription(topics, userData, nil), nil
 	}
-	return nil, fmt.Errorf("subscription V0 conversion error")
+	return nil, fmt.Errorf("%w: subscription V0 is a %T", kafkaschema.ErrTypeMismatch, subscriptionV0)
 }
 
 func (cp *consumerProtocol) deserializeSubscriptionV1(buffer *buffer2.ByteBuffer) (*Subscription, error) {
-	subscriptionV1, err := SubscriptionV1.Read(buffer)
+	subscriptionV1, err := cp.schemas.subscriptionV1.Read(buffer, cp.opts)
 	if err != nil {
-		return NewSubscription(nil, nil, nil), nil
+		return nil, err
 	}
 
 	if Struct, ok := subscriptionV1.(*kafkaschema.Struct); ok {
 		userData, err := Struct.GetByteBuffer(UserDataKeyName)
+		if err != nil {
+			return nil, err
+		}
 		topics, err := cp.deserializeTopics(Struct)
+		if err != nil {
+			return nil, err
+		}
 		ownedPartitions, err := cp.deserializeOwnedPartitions(Struct)
 		if err != nil {
 			return nil, err
 		}
 		return NewSubscription(topics, userData, ownedPartitions), nil
 	}
-	return nil, fmt.Errorf("subscription V0 conversion error")
+	return nil, fmt.Errorf("%w: subscription V1 is a %T", kafkaschema.ErrTypeMismatch, subscriptionV1)
 }
 
 func (cp *consumerProtocol) deserializeTopics(Struct *kafkaschema.Struct) ([]string, error) {
-	topics, err := Struct.GetArray(TopicKeyName)
+	topics, err := Struct.GetStringArray(TopicKeyName)
 	if err != nil {
 		return nil, err
 	}
-
-	ts := make([]string, 0)
-	for _, t := range topics {
-		if stringT, err := util.Interface2String(t); err == nil {
-			ts = append(ts, stringT)
-		}
+	if topics == nil {
+		return make([]string, 0), nil
 	}
-	return ts, nil
+	return topics, nil
 }
 
 func (cp *consumerProtocol) deserializePartitions(assignment *kafkaschema.Struct, topic string) []*common.TopicPartition {
 	tps := make([]*common.TopicPartition, 0)
-	partitions, err := assignment.GetArray(PartitionsKeyName)
+	partitions, err := assignment.GetInt32Array(PartitionsKeyName)
 	if err != nil {
 		return tps
 	}
 
 	for _, partition := range partitions {
-		if intP, err := util.Interface2Int(partition); err == nil {
-			tps = append(tps, common.NewTopicPartition(topic, intP))
-		}
+		tps = append(tps, common.NewTopicPartition(topic, int(partition)))
 	}
 	return tps
 }
 
 func (cp *consumerProtocol) deserializeOwnedPartitions(Struct *kafkaschema.Struct) ([]*common.TopicPartition, error) {
-	topicPartitions, err := Struct.GetArray(OwnedPartitionsKeyName)
+	topicPartitions, err := Struct.GetStructArray(OwnedPartitionsKeyName)
 	return cp.deserializeTopicPartitions1(err, topicPartitions)
 }
 
 func (cp *consumerProtocol) deserializeTopicPartitions(Struct *kafkaschema.Struct) ([]*common.TopicPartition, error) {
-	topicPartitions, err := Struct.GetArray(TopicPartitionsKeyName)
+	topicPartitions, err := Struct.GetStructArray(TopicPartitionsKeyName)
 	return cp.deserializeTopicPartitions1(err, topicPartitions)
 }
 
-func (cp *consumerProtocol) deserializeTopicPartitions1(err error, topicPartitions []interface{}) ([]*common.TopicPartition, error) {
+func (cp *consumerProtocol) deserializeTopicPartitions1(err error, topicPartitions []*kafkaschema.Struct) ([]*common.TopicPartition, error) {
 	if err != nil {
 		return nil, err
 	}
 
 	tps := make([]*common.TopicPartition, 0)
-	for _, tp := range topicPartitions {
-		assignment, ok := tp.(*kafkaschema.Struct)
-		if !ok {
-			return nil, fmt.Errorf("topicPartitions conversion error")
-		}
+	for _, assignment := range topicPartitions {
 		topic, err := assignment.GetString(TopicKeyName)
 		if err != nil {
 			return nil, err
@@ -181,62 +263,89 @@ func (cp *consumerProtocol) deserializeTopicPartitions1(err error, topicPartitio
 }
 
 func (cp *consumerProtocol) deserializeAssignmentV0(buffer *buffer2.ByteBuffer) (*Assignment, error) {
-	assignmentV0, err := AssignmentV0.Read(buffer)
+	assignmentV0, err := cp.schemas.assignmentV0.Read(buffer, cp.opts)
 	if err != nil {
 		return nil, err
 	}
 
 	if Struct, ok := assignmentV0.(*kafkaschema.Struct); ok {
 		userData, err := Struct.GetByteBuffer(UserDataKeyName)
+		if err != nil {
+			return nil, err
+		}
 		pts, err := cp.deserializeTopicPartitions(Struct)
 		if err != nil {
 			return nil, err
 		}
 		return NewAssignment(pts, userData), nil
 	}
-	return nil, fmt.Errorf("assignment V0 conversion error")
+	return nil, fmt.Errorf("%w: assignment V0 is a %T", kafkaschema.ErrTypeMismatch, assignmentV0)
 }
 
 func (cp *consumerProtocol) deserializeAssignmentV1(buffer *buffer2.ByteBuffer) (*Assignment, error) {
 	return cp.deserializeAssignmentV0(buffer)
 }
 
+// DeserializeSubscription reads a subscription of the consumer protocol, errors are
+// DecodeErrors naming the subscription
 func (cp *consumerProtocol) DeserializeSubscription(buffer *buffer2.ByteBuffer) (*Subscription, error) {
+	// the header and the message share the limits
+	cp = cp.WithDecodeOptions(cp.opts.Begin())
+	offset := buffer.AbsolutePosition()
 	version, err := cp.deserializeVersion(buffer)
 	if err != nil {
-		return nil, err
+		return nil, kafkaschema.NameDecodeError(err, SubscriptionName, -1, offset)
 	}
 
 	if version < ConsumerProtocolV0 {
-		return nil, fmt.Errorf("unsupported subscription version: %v", version)
+		err = fmt.Errorf("%w of subscription: %v", kafkaschema.ErrUnknownVersion, version)
+		return nil, kafkaschema.NameDecodeError(kafkaschema.LocateDecodeError(err, VersionKeyName, offset), SubscriptionName, int(version), offset)
 	}
 
+	var subscription *Subscription
 	switch version {
 	case ConsumerProtocolV0:
-		return cp.deserializeSubscriptionV0(buffer)
+		subscription, err = cp.deserializeSubscriptionV0(buffer)
 	case ConsumerProtocolV1:
-		return cp.deserializeSubscriptionV1(buffer)
+		subscription, err = cp.deserializeSubscriptionV1(buffer)
 	default:
-		return cp.deserializeSubscriptionV1(buffer)
+		subscription, err = cp.deserializeSubscriptionV1(buffer)
 	}
+	if err != nil {
+		return nil, kafkaschema.NameDecodeError(err, SubscriptionName, int(version), offset)
+	}
+	subscription.warnings = cp.warnings(buffer, SubscriptionName, version, offset)
+	return subscription, nil
 }
 
+// DeserializeAssignment reads an assignment of the consumer protocol, errors are
+// DecodeErrors naming the assignment
 func (cp *consumerProtocol) DeserializeAssignment(buffer *buffer2.ByteBuffer) (*Assignment, error) {
+	// the header and the message share the limits
+	cp = cp.WithDecodeOptions(cp.opts.Begin())
+	offset := buffer.AbsolutePosition()
 	version, err := cp.deserializeVersion(buffer)
 	if err != nil {
-		return nil, err
+		return nil, kafkaschema.NameDecodeError(err, AssignmentName, -1, offset)
 	}
 
 	if version < ConsumerProtocolV0 {
-		return nil, fmt.Errorf("unsupported assignment version: %v", version)
+		err = fmt.Errorf("%w of assignment: %v", kafkaschema.ErrUnknownVersion, version)
+		return nil, kafkaschema.NameDecodeError(kafkaschema.LocateDecodeError(err, VersionKeyName, offset), AssignmentName, int(version), offset)
 	}
 
+	var assignment *Assignment
 	switch version {
 	case ConsumerProtocolV0:
-		return cp.deserializeAssignmentV0(buffer)
+		assignment, err = cp.deserializeAssignmentV0(buffer)
 	case ConsumerProtocolV1:
-		return cp.deserializeAssignmentV1(buffer)
+		assignment, err = cp.deserializeAssignmentV1(buffer)
 	default:
-		return cp.deserializeAssignmentV1(buffer)
+		assignment, err = cp.deserializeAssignmentV1(buffer)
+	}
+	if err != nil {
+		return nil, kafkaschema.NameDecodeError(err, AssignmentName, int(version), offset)
 	}
+	assignment.warnings = cp.warnings(buffer, AssignmentName, version, offset)
+	return assignment, nil
 }
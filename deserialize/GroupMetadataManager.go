@@ -1,6 +1,7 @@
 package deserialize
 
 import (
+	"errors"
 	"fmt"
 	"kafka_schema/deserialize/common"
 	"kafka_schema/schema"
@@ -14,297 +15,335 @@ const (
 	CurrentGroupKeySchemaVersion  = int16(2)
 )
 
-var once sync.Once
-var Gmm *groupMetadataManager
+var (
+	once    sync.Once
+	initErr error
+)
+
+// Gmm is the decoder set by InitGroupMetadataManager.
+//
+// Deprecated: use NewDecoder.
+var Gmm *Decoder
+
+// Decoder reads the keys and values of the records of the __consumer_offsets topic. A
+// Decoder is immutable and safe for concurrent use, it reads with its own schemas.
+type Decoder struct {
+	registry *schemaRegistry
+	lenient  bool
+	opts     *kafkaschema.DecodeOptions
+}
 
-type groupMetadataManager struct{}
+// DecoderOption configures a Decoder created by NewDecoder
+type DecoderOption func(d *Decoder)
 
+// Lenient makes the decoder lenient, see Decoder.WithLenient
+func Lenient() DecoderOption {
+	return func(d *Decoder) {
+		d.lenient = true
+	}
+}
+
+// Limits makes the decoder read within the limits of the options, see Decoder.WithDecodeOptions
+func Limits(opts *kafkaschema.DecodeOptions) DecoderOption {
+	return func(d *Decoder) {
+		d.opts = opts
+	}
+}
+
+// UseRegistry makes the decoder read with the versions registered in the registry so far,
+// versions registered later are not seen by the decoder
+func UseRegistry(r *Registry) DecoderOption {
+	return func(d *Decoder) {
+		d.registry = r.snapshot()
+	}
+}
+
+// NewDecoder returns a strict decoder without limits of the built-in versions configured by
+// the options
+func NewDecoder(options ...DecoderOption) (*Decoder, error) {
+	d := &Decoder{}
+	for _, option := range options {
+		option(d)
+	}
+	if d.registry == nil {
+		builtin, err := newBuiltinSchemas()
+		if err != nil {
+			return nil, err
+		}
+		d.registry = builtin.registry()
+	}
+	return d, nil
+}
+
+// InitGroupMetadataManager sets Gmm and the schema variables of the package, it returns the
+// error of the first call on every call.
+//
+// Deprecated: use NewDecoder.
 func InitGroupMetadataManager() error {
-	var err error
 	once.Do(func() {
-		err = initSchemas()
-		Gmm = &groupMetadataManager{}
+		var builtin *builtinSchemas
+		if builtin, initErr = newBuiltinSchemas(); initErr != nil {
+			return
+		}
+		builtin.setGlobals()
+		Gmm = &Decoder{registry: builtin.registry()}
 	})
-	return err
+	return initErr
+}
+
+// WithLenient returns a decoder decoding leniently or not. A lenient decoder keeps going past
+// recoverable problems of the values and reports them as warnings of the result: trailing
+// bytes, a subscription or assignment which cannot be decoded and a version newer than the
+// known ones, which is decoded with the latest known schema.
+func (d *Decoder) WithLenient(lenient bool) *Decoder {
+	decoder := *d
+	decoder.lenient = lenient
+	return &decoder
 }
 
-func (gmm *groupMetadataManager) ReadMessageKey(buffer *buffer2.ByteBuffer) (common.BaseKey, error) {
+// WithDecodeOptions returns a decoder reading within the limits of the options, a group
+// metadata value and the subscriptions and assignments of its members share the limits
+func (d *Decoder) WithDecodeOptions(opts *kafkaschema.DecodeOptions) *Decoder {
+	decoder := *d
+	decoder.opts = opts
+	return &decoder
+}
+
+func (d *Decoder) ReadMessageKey(buffer *buffer2.ByteBuffer) (common.BaseKey, error) {
 	if buffer == nil {
 		return nil, fmt.Errorf("read buffer is null")
 	}
 
-	version, key, err := gmm.readBuffer(buffer, common.MessageKey)
+	offset := buffer.AbsolutePosition()
+	version, entry, key, _, err := d.readBuffer(buffer, common.MessageKey, d.opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if version <= CurrentOffsetKeySchemaVersion {
-		return gmm.readOffsetKey(version, key)
-	} else if version == CurrentGroupKeySchemaVersion {
-		return gmm.readGroupMetaDataKey(version, key)
-	} else {
-		return nil, fmt.Errorf("unknown group metadata message version: %v", version)
+	baseKey, err := entry.convertKey(version, key)
+	if err != nil {
+		return nil, kafkaschema.NameDecodeError(err, entry.name, -1, offset)
 	}
+	return baseKey, nil
 }
 
-func (gmm *groupMetadataManager) ReadOffsetMessageValue(buffer *buffer2.ByteBuffer) (*common.OffsetAndMetadata, error) {
+func (d *Decoder) ReadOffsetMessageValue(buffer *buffer2.ByteBuffer) (*common.OffsetAndMetadata, error) {
 	if buffer == nil {
 		return nil, fmt.Errorf("read buffer is null")
 	}
 
-	version, value, err := gmm.readBuffer(buffer, common.OffsetValue)
+	offset := buffer.AbsolutePosition()
+	version, entry, value, warnings, err := d.readBuffer(buffer, common.OffsetValue, d.opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if version == 0 {
-		return gmm.readOffsetMessageValueV0(value)
-	} else if version == 1 {
-		return gmm.readOffsetMessageValueV1(value)
-	} else if version == 2 {
-		return gmm.readOffsetMessageValueV2(value)
-	} else if version == 3 {
-		return gmm.readOffsetMessageValueV3(value)
-	} else {
-		return nil, fmt.Errorf("unknown offset message version: %v", version)
+	offsetAndMetadata, err := entry.convertOffsetValue(version, value)
+	if err != nil {
+		return nil, kafkaschema.NameDecodeError(err, entry.name, int(version), offset)
 	}
+	for _, warning := range offsetAndMetadata.Warnings {
+		warnings = append(warnings, kafkaschema.NameDecodeError(warning, entry.name, int(version), offset))
+	}
+	offsetAndMetadata.Warnings = warnings
+	return offsetAndMetadata, nil
 }
 
-func (gmm *groupMetadataManager) ReadGroupMessageValue(groupID string, buffer *buffer2.ByteBuffer) (*common.GroupMetadata, error) {
+func (d *Decoder) ReadGroupMessageValue(groupID string, buffer *buffer2.ByteBuffer) (*common.GroupMetadata, error) {
 	if buffer == nil {
 		return nil, fmt.Errorf("read buffer is null")
 	}
 
-	version, value, err := gmm.readBuffer(buffer, common.GroupValue)
+	offset := buffer.AbsolutePosition()
+	opts := d.opts.Begin()
+	version, entry, value, warnings, err := d.readBuffer(buffer, common.GroupValue, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if version >= 0 && version <= 3 {
-		return gmm.readGroupMessageValue(groupID, value, version)
-	} else {
-		return nil, fmt.Errorf("unknown group metadata message version: %v", version)
+	groupMetadata, err := entry.convertGroupValue(d.WithDecodeOptions(opts), groupID, version, value)
+	if err != nil {
+		return nil, kafkaschema.NameDecodeError(err, entry.name, int(version), offset)
 	}
+	named := make([]error, 0, len(groupMetadata.Warnings())+len(warnings))
+	for _, warning := range groupMetadata.Warnings() {
+		named = append(named, kafkaschema.NameDecodeError(warning, entry.name, int(version), offset))
+	}
+	groupMetadata.SetWarnings(append(named, warnings...))
+	return groupMetadata, nil
 }
 
-func (gmm *groupMetadataManager) readBuffer(buffer *buffer2.ByteBuffer, bt common.BufferType) (version int16, data interface{}, err error) {
+// readBuffer reads the version then the message of the buffer type with the schema registered
+// for the version, errors and warnings are DecodeErrors naming the message
+func (d *Decoder) readBuffer(buffer *buffer2.ByteBuffer, bt common.BufferType, opts *kafkaschema.DecodeOptions) (version int16, entry *registryEntry, data *kafkaschema.Struct, warnings []error, err error) {
+	offset := buffer.AbsolutePosition()
 	if version, err = buffer.GetInt16(); err != nil {
-		err = fmt.Errorf("get version failed: %v", err)
+		err = kafkaschema.NameDecodeError(kafkaschema.LocateDecodeError(err, "version", offset), messageName(bt, -1), -1, offset)
 		return
 	}
+	// the version of a key tells the key schema apart, the key schemas are not versioned
+	schemaVersion := int(version)
+	if bt == common.MessageKey {
+		schemaVersion = -1
+	}
 
-	var schema *kafkaschema.Schema
-	switch bt {
-	case common.MessageKey:
-		schema, err = gmm.schemaForKey(int(version))
-	case common.OffsetValue:
-		schema, err = gmm.schemaForOffsetValue(int(version))
-	case common.GroupValue:
-		schema, err = gmm.schemaForGroupValue(int(version))
+	entry, err = d.registry.lookup(bt, version)
+	if err != nil && d.lenient && bt != common.MessageKey && errors.Is(err, kafkaschema.ErrUnknownVersion) {
+		if latest, latestEntry := d.registry.latest(bt); latestEntry != nil && version > latest {
+			entry, err = latestEntry, nil
+			warning := fmt.Errorf("%w: %d, decoded with version %d", kafkaschema.ErrUnknownVersion, version, latest)
+			warnings = append(warnings, kafkaschema.LocateDecodeError(warning, "version", offset))
+		}
 	}
 	if err != nil {
+		err = kafkaschema.NameDecodeError(kafkaschema.LocateDecodeError(err, "version", offset), messageName(bt, version), schemaVersion, offset)
 		return
 	}
 
-	data, err = schema.Read(buffer)
+	value, err := entry.schema.Read(buffer, opts)
+	if err != nil {
+		err = kafkaschema.NameDecodeError(err, entry.name, schemaVersion, offset)
+		return
+	}
+	var ok bool
+	if data, ok = value.(*kafkaschema.Struct); !ok {
+		err = kafkaschema.NameDecodeError(fmt.Errorf("%w: message is a %T", kafkaschema.ErrTypeMismatch, value), entry.name, schemaVersion, offset)
+		return
+	}
+	if d.lenient && bt != common.MessageKey && buffer.HasRemaining() {
+		warning := fmt.Errorf("%w: %d bytes after the value", kafkaschema.ErrTrailingBytes, buffer.Remaining())
+		warnings = append(warnings, kafkaschema.LocateDecodeError(warning, "", buffer.AbsolutePosition()))
+	}
+	for i, warning := range warnings {
+		warnings[i] = kafkaschema.NameDecodeError(warning, entry.name, schemaVersion, offset)
+	}
 	return
 }
 
-func (gmm *groupMetadataManager) schemaForKey(version int) (*kafkaschema.Schema, error) {
-	schema, ok := MessageTypeSchemas[version]
-	if !ok {
-		return nil, fmt.Errorf("unknown message key schema version: %v", version)
+// messageName returns the name the built-in schemas of the buffer type are registered under,
+// the key schema depends on the version
+func messageName(bt common.BufferType, version int16) string {
+	switch bt {
+	case common.OffsetValue:
+		return OffsetCommitValueName
+	case common.GroupValue:
+		return GroupMetadataValueName
 	}
-	return schema, nil
-}
-
-func (gmm *groupMetadataManager) schemaForOffsetValue(version int) (*kafkaschema.Schema, error) {
-	schema, ok := OffsetValueSchemas[version]
-	if !ok {
-		return nil, fmt.Errorf("unknown offset schema version: %v", version)
+	if version == CurrentGroupKeySchemaVersion {
+		return GroupMetadataKeyName
 	}
-	return schema, nil
+	return OffsetCommitKeyName
 }
 
-func (gmm *groupMetadataManager) schemaForGroupValue(version int) (*kafkaschema.Schema, error) {
-	schema, ok := GroupValueSchemas[version]
-	if !ok {
-		return nil, fmt.Errorf("unknown group metadata version: %v", version)
+// convertOffsetKey is the built-in converter of the offset commit keys
+func convertOffsetKey(version int16, key *kafkaschema.Struct) (common.BaseKey, error) {
+	group, err := key.GetString(GroupKey)
+	if err != nil {
+		return nil, err
 	}
-	return schema, nil
-}
-
-func (gmm *groupMetadataManager) readOffsetKey(version int16, key interface{}) (*common.OffsetKey, error) {
-	if Struct, ok := key.(*kafkaschema.Struct); ok {
-		group, err := Struct.GetStringByField(OffsetKeyGroupField)
-		if err != nil {
-			return nil, err
-		}
-		topic, err := Struct.GetStringByField(OffsetKeyTopicField)
-		if err != nil {
-			return nil, err
-		}
-		partition, err := Struct.GetIntByField(OffsetKeyPartitionField)
-		if err != nil {
-			return nil, err
-		}
-		return common.NewOffsetKey(version, common.NewGroupTopicPartition(group, common.NewTopicPartition(topic, partition))), nil
+	topic, err := key.GetString(TopicKey)
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("offset key type conversion error")
-}
-
-func (gmm *groupMetadataManager) readGroupMetaDataKey(version int16, key interface{}) (*common.GroupMetadataKey, error) {
-	if Struct, ok := key.(*kafkaschema.Struct); ok {
-		group, err := Struct.GetStringByField(GroupKeyGroupField)
-		if err != nil {
-			return nil, err
-		}
-		return common.NewGroupMetadataKey(version, group), nil
+	partition, err := key.GetInt(PartitionKey)
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("group meta data key type conversion error")
+	return common.NewOffsetKey(version, common.NewGroupTopicPartition(group, common.NewTopicPartition(topic, partition))), nil
 }
 
-func (gmm *groupMetadataManager) readOffsetMessageValueV0(value interface{}) (*common.OffsetAndMetadata, error) {
-	if Struct, ok := value.(*kafkaschema.Struct); ok {
-		offset, err := Struct.GetInt64ByField(OffsetValueOffsetFieldV0)
-		if err != nil {
-			return nil, err
-		}
-		metadata, err := Struct.GetStringByField(OffsetValueMetadataFieldV0)
-		if err != nil {
-			return nil, err
-		}
-		timestamp, err := Struct.GetInt64ByField(OffsetValueTimestampFieldV0)
-		if err != nil {
-			return nil, err
-		}
-		return common.NewOffsetAndMetadata1(offset, metadata, timestamp), nil
+// convertGroupMetadataKey is the built-in converter of the group metadata keys
+func convertGroupMetadataKey(version int16, key *kafkaschema.Struct) (common.BaseKey, error) {
+	group, err := key.GetString(GroupKey)
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("offset message value V0 type conversion error")
+	return common.NewGroupMetadataKey(version, group), nil
 }
 
-func (gmm *groupMetadataManager) readOffsetMessageValueV1(value interface{}) (*common.OffsetAndMetadata, error) {
-	if Struct, ok := value.(*kafkaschema.Struct); ok {
-		offset, err := Struct.GetInt64ByField(OffsetValueOffsetFieldV1)
-		if err != nil {
-			return nil, err
-		}
-		metadata, err := Struct.GetStringByField(OffsetValueMetadataFieldV1)
-		if err != nil {
-			return nil, err
-		}
-		commitTimestamp, err := Struct.GetInt64ByField(OffsetValueCommitTimestampFieldV1)
-		if err != nil {
+// convertOffsetValue is the built-in converter of the offset commit values
+func convertOffsetValue(version int16, value *kafkaschema.Struct) (*common.OffsetAndMetadata, error) {
+	offset, err := value.GetInt64(OffsetKey)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := value.GetString(MetadataKey)
+	if err != nil {
+		return nil, err
+	}
+	commitTimestamp, err := value.GetInt64(CommitTimestampKey)
+	if err != nil {
+		return nil, err
+	}
+	// the leader epoch exists from V3 on and the expire timestamp in V1 only
+	leaderEpoch, expireTimestamp := common.NoLeaderEpoch, int64(-1)
+	if value.HasField(LeaderEpochKey) {
+		if leaderEpoch, err = kafkaschema.Get[int32](value, LeaderEpochKey); err != nil {
 			return nil, err
 		}
-		expireTimestamp, err := Struct.GetInt64ByField(OffsetValueExpireTimestampFieldV1)
-		if err != nil {
+	}
+	if value.HasField(ExpireTimestampKey) {
+		if expireTimestamp, err = value.GetInt64(ExpireTimestampKey); err != nil {
 			return nil, err
 		}
-
-		if expireTimestamp == int64(-1) {
-			return common.NewOffsetAndMetadata1(offset, metadata, commitTimestamp), nil
-		} else {
-			return common.NewOffsetAndMetadata2(offset, metadata, commitTimestamp, expireTimestamp), nil
-		}
 	}
-	return nil, fmt.Errorf("offset message value V1 type conversion error")
-}
 
-func (gmm *groupMetadataManager) readOffsetMessageValueV2(value interface{}) (*common.OffsetAndMetadata, error) {
-	if Struct, ok := value.(*kafkaschema.Struct); ok {
-		offset, err := Struct.GetInt64ByField(OffsetValueOffsetFieldV2)
-		if err != nil {
-			return nil, err
-		}
-		metadata, err := Struct.GetStringByField(OffsetValueMetadataFieldV2)
-		if err != nil {
-			return nil, err
-		}
-		commitTimestamp, err := Struct.GetInt64ByField(OffsetValueCommitTimestampFieldV2)
-		if err != nil {
-			return nil, err
-		}
+	if leaderEpoch != common.NoLeaderEpoch {
+		return common.NewOffsetAndMetadata3(offset, leaderEpoch, metadata, commitTimestamp), nil
+	} else if expireTimestamp != int64(-1) {
+		return common.NewOffsetAndMetadata2(offset, metadata, commitTimestamp, expireTimestamp), nil
+	} else {
 		return common.NewOffsetAndMetadata1(offset, metadata, commitTimestamp), nil
 	}
-	return nil, fmt.Errorf("offset message value V2 type conversion error")
 }
 
-func (gmm *groupMetadataManager) readOffsetMessageValueV3(value interface{}) (*common.OffsetAndMetadata, error) {
-	if Struct, ok := value.(*kafkaschema.Struct); ok {
-		offset, err := Struct.GetInt64ByField(OffsetValueOffsetFieldV3)
-		if err != nil {
-			return nil, err
-		}
-		leaderEpoch, err := Struct.GetIntByField(OffsetValueLeaderEpochFieldV3)
-		if err != nil {
-			return nil, err
-		}
-		metadata, err := Struct.GetStringByField(OffsetValueMetadataFieldV3)
-		if err != nil {
-			return nil, err
-		}
-		commitTimestamp, err := Struct.GetInt64ByField(OffsetValueCommitTimestampFieldV3)
-		if err != nil {
-			return nil, err
-		}
-
-		if leaderEpoch < 0 {
-			return common.NewOffsetAndMetadata3(offset, nil, metadata, commitTimestamp), nil
-		} else {
-			return common.NewOffsetAndMetadata3(offset, leaderEpoch, metadata, commitTimestamp), nil
-		}
+// convertGroupValue is the built-in converter of the group metadata values
+func convertGroupValue(d *Decoder, groupId string, version int16, value *kafkaschema.Struct) (*common.GroupMetadata, error) {
+	generationId, err := value.GetInt(GenerationKey)
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("offset message value V3 type conversion error")
-}
-
-func (gmm *groupMetadataManager) readGroupMessageValue(groupId string, value interface{}, version int16) (*common.GroupMetadata, error) {
-	if Struct, ok := value.(*kafkaschema.Struct); ok {
-		generationId, err := Struct.GetInt(GenerationKey)
-		if err != nil {
-			return nil, err
-		}
-		protocolType, err := Struct.GetString(ProtocolTypeKey)
-		if err != nil {
-			return nil, err
-		}
-		protocol, err := Struct.GetString(ProtocolKey)
-		if err != nil {
-			return nil, err
-		}
-		leaderId, err := Struct.GetString(LeaderKey)
-		if err != nil {
-			return nil, err
-		}
-		memberMetadataArray, err := Struct.GetArray(MembersKey)
-		if err != nil {
-			return nil, err
-		}
-		initialState := gmm.readInitialState(err, memberMetadataArray)
-		currentStateTimestamp, err := gmm.readCurrentStateTimestamp(version, Struct)
-		if err != nil {
-			return nil, err
-		}
-		members, err := gmm.readMembers(version, groupId, protocol, protocolType, memberMetadataArray)
-		if err != nil {
-			return nil, err
-		}
-		return common.LoadGroup(
-			groupId,
-			initialState,
-			generationId,
-			protocolType,
-			protocol,
-			leaderId,
-			currentStateTimestamp,
-			members,
-			time.Now().UnixNano()/1e6,
-		), nil
-	}
-	return nil, fmt.Errorf("group message value type conversion error")
+	protocolType, err := value.GetString(ProtocolTypeKey)
+	if err != nil {
+		return nil, err
+	}
+	protocol, err := value.GetString(ProtocolKey)
+	if err != nil {
+		return nil, err
+	}
+	leaderId, err := value.GetString(LeaderKey)
+	if err != nil {
+		return nil, err
+	}
+	memberMetadataArray, err := value.GetArray(MembersKey)
+	if err != nil {
+		return nil, err
+	}
+	initialState := d.readInitialState(err, memberMetadataArray)
+	currentStateTimestamp, err := d.readCurrentStateTimestamp(version, value)
+	if err != nil {
+		return nil, err
+	}
+	members, warnings, err := d.readMembers(groupId, protocol, protocolType, memberMetadataArray)
+	if err != nil {
+		return nil, err
+	}
+	groupMetadata := common.LoadGroup(
+		groupId,
+		initialState,
+		generationId,
+		protocolType,
+		protocol,
+		leaderId,
+		currentStateTimestamp,
+		members,
+		time.Now().UnixNano()/1e6,
+	)
+	groupMetadata.AddWarnings(warnings...)
+	return groupMetadata, nil
 }
 
-func (gmm *groupMetadataManager) readInitialState(err error, memberMetadataArray []interface{}) (initialState common.GroupState) {
+func (d *Decoder) readInitialState(err error, memberMetadataArray []interface{}) (initialState common.GroupState) {
 	if err != nil || memberMetadataArray == nil {
 		return common.Empty
 	} else {
@@ -312,93 +351,119 @@ func (gmm *groupMetadataManager) readInitialState(err error, memberMetadataArray
 	}
 }
 
-func (gmm *groupMetadataManager) readCurrentStateTimestamp(version int16, Struct *kafkaschema.Struct) (timestamp int64, err error) {
-	if version == 2 && Struct.HasField(CurrentStateTimestampKey) {
+func (d *Decoder) readCurrentStateTimestamp(version int16, Struct *kafkaschema.Struct) (timestamp int64, err error) {
+	if version >= 2 && Struct.HasField(CurrentStateTimestampKey) {
 		timestamp, err = Struct.GetInt64(CurrentStateTimestampKey)
 		return
 	}
 	return
 }
 
-func (gmm *groupMetadataManager) readMembers(version int16, groupId, protocol, protocolType string, memberMetadataArray []interface{}) ([]*common.MemberMetadata, error) {
+// memberMetadataValue is a member of the group metadata value, for every schema version
+type memberMetadataValue struct {
+	MemberId        string              `kafka:"member_id"`
+	GroupInstanceId *string             `kafka:"group_instance_id"`
+	ClientId        string              `kafka:"client_id"`
+	ClientHost      string              `kafka:"client_host"`
+	Subscription    *buffer2.ByteBuffer `kafka:"subscription"`
+	Assignment      *buffer2.ByteBuffer `kafka:"assignment"`
+}
+
+// readMembers reads the members of the group, a lenient manager reports the subscriptions
+// and assignments it cannot decode as warnings
+func (d *Decoder) readMembers(groupId, protocol, protocolType string, memberMetadataArray []interface{}) ([]*common.MemberMetadata, []error, error) {
 
 	memberMetadataResult := make([]*common.MemberMetadata, 0)
-	for _, memberMetadata := range memberMetadataArray {
+	warnings := make([]error, 0)
+	for i, memberMetadata := range memberMetadataArray {
 		Struct, ok := memberMetadata.(*kafkaschema.Struct)
 		if !ok {
-			return nil, fmt.Errorf("member metadata conversion error")
+			return nil, nil, fmt.Errorf("%w: member metadata is a %T", kafkaschema.ErrTypeMismatch, memberMetadata)
 		}
+		path := fmt.Sprintf("%s[%d]", MembersKey, i)
 
-		memberId, err := Struct.GetString(MemberIdKey)
-		if err != nil {
-			return nil, err
+		var member memberMetadataValue
+		if err := Struct.Bind(&member); err != nil {
+			return nil, nil, kafkaschema.LocateDecodeError(err, path, -1)
 		}
-		clientId, err := Struct.GetString(ClientIdKey)
-		if err != nil {
-			return nil, err
+		var groupInstanceId string
+		if member.GroupInstanceId != nil {
+			groupInstanceId = *member.GroupInstanceId
 		}
-		groupInstanceId, err := gmm.readGroupInstanceId(version, Struct)
+		subscriptionOffset, assignmentOffset := bufferOffset(member.Subscription), bufferOffset(member.Assignment)
+		subscription, subscriptionWarnings, err := d.readSubscription(protocol, member.Subscription)
 		if err != nil {
-			return nil, err
+			err = kafkaschema.LocateDecodeError(err, path+"."+SubscriptionKey, subscriptionOffset)
+			if !d.lenient {
+				return nil, nil, err
+			}
+			warnings = append(warnings, err)
 		}
-		clientHost, err := Struct.GetString(ClientHostKey)
-		if err != nil {
-			return nil, err
+		for _, warning := range subscriptionWarnings {
+			warnings = append(warnings, kafkaschema.LocateDecodeError(warning, path+"."+SubscriptionKey, subscriptionOffset))
 		}
-		subscription, err := gmm.readSubscription(protocol, Struct)
+		partitions, assignmentWarnings, err := d.readAssignment(member.Assignment)
 		if err != nil {
-			return nil, err
+			err = kafkaschema.LocateDecodeError(err, path+"."+AssignmentKey, assignmentOffset)
+			if !d.lenient {
+				return nil, nil, err
+			}
+			warnings = append(warnings, err)
 		}
-		partitions, err := gmm.readAssignment(Struct)
-		if err != nil {
-			return nil, err
+		for _, warning := range assignmentWarnings {
+			warnings = append(warnings, kafkaschema.LocateDecodeError(warning, path+"."+AssignmentKey, assignmentOffset))
 		}
 
 		memberMetadataResult = append(memberMetadataResult, common.NewMemberMetadata(
-			memberId,
+			member.MemberId,
 			groupId,
 			groupInstanceId,
-			clientId,
-			clientHost,
+			member.ClientId,
+			member.ClientHost,
 			protocolType,
 			subscription,
 			partitions))
 	}
-	return memberMetadataResult, nil
+	return memberMetadataResult, warnings, nil
 }
 
-func (gmm *groupMetadataManager) readGroupInstanceId(version int16, Struct *kafkaschema.Struct) (groupInstanceId string, err error) {
-	if version >= 3 {
-		groupInstanceId, err = Struct.GetString(GroupInstanceIdKey)
-		return
+// bufferOffset returns the offset of a member's subscription or assignment, -1 when it is
+// null as a registered schema may declare it nullable
+func bufferOffset(b *buffer2.ByteBuffer) int {
+	if b == nil {
+		return -1
 	}
-	return
+	return b.AbsolutePosition()
 }
 
-func (gmm *groupMetadataManager) readSubscription(protocol string, Struct *kafkaschema.Struct) (map[string][]string, error) {
-	subscriptionBuffer, err := Struct.GetByteBuffer(SubscriptionKey)
-	if err != nil {
-		return nil, err
+func (d *Decoder) readSubscription(protocol string, subscriptionBuffer *buffer2.ByteBuffer) (map[string][]string, []error, error) {
+	if subscriptionBuffer == nil {
+		return nil, nil, fmt.Errorf("%w: the subscription is null", kafkaschema.ErrNegativeLength)
 	}
-	subscription, err := ConsumerProtocol().DeserializeSubscription(subscriptionBuffer)
+	subscription, err := d.ConsumerProtocol().DeserializeSubscription(subscriptionBuffer)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	s := make(map[string][]string)
 	s[protocol] = subscription.Topics()
-	return s, nil
+	return s, subscription.Warnings(), nil
 }
 
-func (gmm *groupMetadataManager) readAssignment(Struct *kafkaschema.Struct) ([]*common.TopicPartition, error) {
-	assignmentBuffer, err := Struct.GetByteBuffer(AssignmentKey)
-	if err != nil {
-		return nil, err
+func (d *Decoder) readAssignment(assignmentBuffer *buffer2.ByteBuffer) ([]*common.TopicPartition, []error, error) {
+	if assignmentBuffer == nil {
+		return nil, nil, fmt.Errorf("%w: the assignment is null", kafkaschema.ErrNegativeLength)
 	}
-	assignment, err := ConsumerProtocol().DeserializeAssignment(assignmentBuffer)
+	assignment, err := d.ConsumerProtocol().DeserializeAssignment(assignmentBuffer)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return assignment.Partitions(), nil
+	return assignment.Partitions(), assignment.Warnings(), nil
+}
+
+// ConsumerProtocol returns the consumer protocol of the decoder, it reads with the schemas,
+// the leniency and the limits of the decoder
+func (d *Decoder) ConsumerProtocol() *consumerProtocol {
+	return &consumerProtocol{schemas: d.registry.consumerProtocol, lenient: d.lenient, opts: d.opts}
 }
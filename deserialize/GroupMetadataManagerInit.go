@@ -2,11 +2,17 @@ package deserialize
 
 import (
 	"fmt"
+	"kafka_schema/deserialize/common"
+	"kafka_schema/deserialize/message"
 	"kafka_schema/schema"
-
+	"kafka_schema/schema/spec"
 )
 
 const (
+	GroupKey     = "group"
+	TopicKey     = "topic"
+	PartitionKey = "partition"
+
 	ProtocolTypeKey          = "protocol_type"
 	GenerationKey            = "generation"
 	ProtocolKey              = "protocol"
@@ -14,6 +20,12 @@ const (
 	CurrentStateTimestampKey = "current_state_timestamp"
 	MembersKey               = "members"
 
+	OffsetKey          = "offset"
+	LeaderEpochKey     = "leader_epoch"
+	MetadataKey        = "metadata"
+	CommitTimestampKey = "commit_timestamp"
+	ExpireTimestampKey = "expire_timestamp"
+
 	MemberIdKey         = "member_id"
 	GroupInstanceIdKey  = "group_instance_id"
 	ClientIdKey         = "client_id"
@@ -22,8 +34,17 @@ const (
 	SessionTimeoutKey   = "session_timeout"
 	SubscriptionKey     = "subscription"
 	AssignmentKey       = "assignment"
+
+	OffsetCommitKeyName    = "OffsetCommitKey"
+	GroupMetadataKeyName   = "GroupMetadataKey"
+	OffsetCommitValueName  = "OffsetCommitValue"
+	GroupMetadataValueName = "GroupMetadataValue"
+	MemberMetadataName     = "MemberMetadata"
 )
 
+// The schemas of the package, they are set by InitGroupMetadataManager.
+//
+// Deprecated: the schemas of a Decoder are its own, see Decoder.RegisteredSchemas.
 var (
 	MessageTypeSchemas map[int]*kafkaschema.Schema
 	OffsetValueSchemas map[int]*kafkaschema.Schema
@@ -37,295 +58,144 @@ var (
 	GroupMetadataKeySchema *kafkaschema.Schema
 	GroupKeyGroupField     *kafkaschema.BoundField
 
-	OffsetCommitValueSchemaV0   *kafkaschema.Schema
-	OffsetValueOffsetFieldV0    *kafkaschema.BoundField
-	OffsetValueMetadataFieldV0  *kafkaschema.BoundField
-	OffsetValueTimestampFieldV0 *kafkaschema.BoundField
-
-	OffsetCommitValueSchemaV1         *kafkaschema.Schema
-	OffsetValueOffsetFieldV1          *kafkaschema.BoundField
-	OffsetValueMetadataFieldV1        *kafkaschema.BoundField
-	OffsetValueCommitTimestampFieldV1 *kafkaschema.BoundField
-	OffsetValueExpireTimestampFieldV1 *kafkaschema.BoundField
-
-	OffsetCommitValueSchemaV2         *kafkaschema.Schema
-	OffsetValueOffsetFieldV2          *kafkaschema.BoundField
-	OffsetValueMetadataFieldV2        *kafkaschema.BoundField
-	OffsetValueCommitTimestampFieldV2 *kafkaschema.BoundField
-
-	OffsetCommitValueSchemaV3         *kafkaschema.Schema
-	OffsetValueOffsetFieldV3          *kafkaschema.BoundField
-	OffsetValueLeaderEpochFieldV3     *kafkaschema.BoundField
-	OffsetValueMetadataFieldV3        *kafkaschema.BoundField
-	OffsetValueCommitTimestampFieldV3 *kafkaschema.BoundField
+	OffsetCommitValueSchemaV0 *kafkaschema.Schema
+	OffsetCommitValueSchemaV1 *kafkaschema.Schema
+	OffsetCommitValueSchemaV2 *kafkaschema.Schema
+	OffsetCommitValueSchemaV3 *kafkaschema.Schema
+	OffsetCommitValueSchemaV4 *kafkaschema.Schema
 
 	GroupMetadataValueSchemaV0 *kafkaschema.Schema
 	GroupMetadataValueSchemaV1 *kafkaschema.Schema
 	GroupMetadataValueSchemaV2 *kafkaschema.Schema
 	GroupMetadataValueSchemaV3 *kafkaschema.Schema
+	GroupMetadataValueSchemaV4 *kafkaschema.Schema
 
 	MemberMetadataV0 *kafkaschema.Schema
 	MemberMetadataV1 *kafkaschema.Schema
 	MemberMetadataV2 *kafkaschema.Schema
 	MemberMetadataV3 *kafkaschema.Schema
+	MemberMetadataV4 *kafkaschema.Schema
 )
 
-func initSchemas() (err error) {
-	err = initKeySchemas()
+// builtinSchemas holds the built-in schemas of the package by version. They are loaded from
+// the message specifications of the message package, a new version of a record is a change
+// of its specification.
+type builtinSchemas struct {
+	offsetCommitKeys  map[int]*kafkaschema.Schema
+	groupMetadataKeys map[int]*kafkaschema.Schema
+	offsetValues      map[int]*kafkaschema.Schema
+	groupValues       map[int]*kafkaschema.Schema
+	memberMetadata    map[int]*kafkaschema.Schema
+	consumerProtocol  *consumerProtocolSchemas
+}
+
+func newBuiltinSchemas() (*builtinSchemas, error) {
+	b := &builtinSchemas{}
+	offsetCommitKey, err := loadSchemas(OffsetCommitKeyName)
 	if err != nil {
-		return
+		return nil, err
 	}
-	err = initOffsetValueSchemas()
+	groupMetadataKey, err := loadSchemas(GroupMetadataKeyName)
 	if err != nil {
-		return
+		return nil, err
 	}
-	err = InitConsumerProtocol()
+	offsetCommitValue, err := loadSchemas(OffsetCommitValueName)
 	if err != nil {
-		return
-	}
-	err = initGroupValueSchemas()
-	return
-}
-
-func initKeySchemas() (err error) {
-	// offset key
-	if OffsetCommitKeySchema, err = kafkaschema.NewSchema(
-		kafkaschema.NewField("group", kafkaschema.STRING),
-		kafkaschema.NewField("topic", kafkaschema.STRING),
-		kafkaschema.NewField("partition", kafkaschema.INT32),
-	); err != nil {
-		return fmt.Errorf("initKeySchemas %s", err)
-	}
-
-	if OffsetKeyGroupField, err = OffsetCommitKeySchema.Get("group"); err != nil {
-		return fmt.Errorf("initKeySchemas %s", err)
-	}
-	if OffsetKeyTopicField, err = OffsetCommitKeySchema.Get("topic"); err != nil {
-		return fmt.Errorf("initKeySchemas %s", err)
-	}
-	if OffsetKeyPartitionField, err = OffsetCommitKeySchema.Get("partition"); err != nil {
-		return fmt.Errorf("initKeySchemas %s", err)
-	}
-
-	// group metadata key
-	if GroupMetadataKeySchema, err = kafkaschema.NewSchema(
-		kafkaschema.NewField("group", kafkaschema.STRING),
-	); err != nil {
-		return fmt.Errorf("initKeySchemas %s", err)
-	}
-
-	if GroupKeyGroupField, err = GroupMetadataKeySchema.Get("group"); err != nil {
-		return fmt.Errorf("initKeySchemas %s", err)
-	}
-
-	MessageTypeSchemas = make(map[int]*kafkaschema.Schema)
-	MessageTypeSchemas[0] = OffsetCommitKeySchema
-	MessageTypeSchemas[1] = OffsetCommitKeySchema
-	MessageTypeSchemas[2] = GroupMetadataKeySchema
-	return
-}
-
-func initOffsetValueSchemas() (err error) {
-	if err = initOffsetValueSchemasV0(); err != nil {
-		return
-	}
-	if err = initOffsetValueSchemasV1(); err != nil {
-		return
-	}
-	if err = initOffsetValueSchemasV2(); err != nil {
-		return
-	}
-	if err = initOffsetValueSchemasV3(); err != nil {
-		return
-	}
-
-	OffsetValueSchemas = make(map[int]*kafkaschema.Schema)
-	OffsetValueSchemas[0] = OffsetCommitValueSchemaV0
-	OffsetValueSchemas[1] = OffsetCommitValueSchemaV1
-	OffsetValueSchemas[2] = OffsetCommitValueSchemaV2
-	OffsetValueSchemas[3] = OffsetCommitValueSchemaV3
-	return
-}
-
-func initOffsetValueSchemasV0() (err error) {
-	if OffsetCommitValueSchemaV0, err = kafkaschema.NewSchema(
-		kafkaschema.NewField("offset", kafkaschema.INT64),
-		kafkaschema.NewField1("metadata", kafkaschema.STRING, "Associated metadata.", ""),
-		kafkaschema.NewField("timestamp", kafkaschema.INT64),
-	); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	if OffsetValueOffsetFieldV0, err = OffsetCommitValueSchemaV0.Get("offset"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	if OffsetValueMetadataFieldV0, err = OffsetCommitValueSchemaV0.Get("metadata"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	if OffsetValueTimestampFieldV0, err = OffsetCommitValueSchemaV0.Get("timestamp"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	return
-}
-
-func initOffsetValueSchemasV1() (err error) {
-	if OffsetCommitValueSchemaV1, err = kafkaschema.NewSchema(
-		kafkaschema.NewField("offset", kafkaschema.INT64),
-		kafkaschema.NewField1("metadata", kafkaschema.STRING, "Associated metadata.", ""),
-		kafkaschema.NewField("commit_timestamp", kafkaschema.INT64),
-		kafkaschema.NewField("expire_timestamp", kafkaschema.INT64),
-	); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	if OffsetValueOffsetFieldV1, err = OffsetCommitValueSchemaV1.Get("offset"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	if OffsetValueMetadataFieldV1, err = OffsetCommitValueSchemaV1.Get("metadata"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
+		return nil, err
 	}
-	if OffsetValueCommitTimestampFieldV1, err = OffsetCommitValueSchemaV1.Get("commit_timestamp"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	if OffsetValueExpireTimestampFieldV1, err = OffsetCommitValueSchemaV1.Get("expire_timestamp"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	return
-}
-
-func initOffsetValueSchemasV2() (err error) {
-	if OffsetCommitValueSchemaV2, err = kafkaschema.NewSchema(
-		kafkaschema.NewField("offset", kafkaschema.INT64),
-		kafkaschema.NewField1("metadata", kafkaschema.STRING, "Associated metadata.", ""),
-		kafkaschema.NewField("commit_timestamp", kafkaschema.INT64),
-	); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
+	groupMetadataValue, err := loadSchemas(GroupMetadataValueName)
+	if err != nil {
+		return nil, err
 	}
-	if OffsetValueOffsetFieldV2, err = OffsetCommitValueSchemaV2.Get("offset"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
+	b.offsetCommitKeys = offsetCommitKey.Schemas()
+	b.groupMetadataKeys = groupMetadataKey.Schemas()
+	b.offsetValues = offsetCommitValue.Schemas()
+	b.groupValues = groupMetadataValue.Schemas()
+	for _, field := range groupMetadataValue.Fields() {
+		if field.Name() == MembersKey && field.Element() != nil {
+			b.memberMetadata = field.Element().Schemas()
+		}
 	}
-	if OffsetValueMetadataFieldV2, err = OffsetCommitValueSchemaV2.Get("metadata"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
+	if b.memberMetadata == nil {
+		return nil, fmt.Errorf("%s has no %s field of %s structs", GroupMetadataValueName, MembersKey, MemberMetadataName)
 	}
-	if OffsetValueCommitTimestampFieldV2, err = OffsetCommitValueSchemaV2.Get("commit_timestamp"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
+	if b.consumerProtocol, err = newConsumerProtocolSchemas(); err != nil {
+		return nil, err
 	}
-	return
+	return b, nil
 }
 
-func initOffsetValueSchemasV3() (err error) {
-	if OffsetCommitValueSchemaV3, err = kafkaschema.NewSchema(
-		kafkaschema.NewField("offset", kafkaschema.INT64),
-		kafkaschema.NewField("leader_epoch", kafkaschema.INT32),
-		kafkaschema.NewField1("metadata", kafkaschema.STRING, "Associated metadata.", ""),
-		kafkaschema.NewField("commit_timestamp", kafkaschema.INT64),
-	); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	if OffsetValueOffsetFieldV3, err = OffsetCommitValueSchemaV3.Get("offset"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	if OffsetValueLeaderEpochFieldV3, err = OffsetCommitValueSchemaV3.Get("leader_epoch"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	if OffsetValueMetadataFieldV3, err = OffsetCommitValueSchemaV3.Get("metadata"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
-	}
-	if OffsetValueCommitTimestampFieldV3, err = OffsetCommitValueSchemaV3.Get("commit_timestamp"); err != nil {
-		return fmt.Errorf("initOffsetValueSchemas %s", err)
+// loadSchemas loads the definition of every version of the message from its specification
+func loadSchemas(name string) (*kafkaschema.VersionedSchema, error) {
+	m, err := spec.LoadFS(message.Specs, name+".json")
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %v", name, err)
 	}
-	return
+	return m.VersionedSchema()
 }
 
-func initGroupValueSchemas() (err error) {
-	if err = initMemberMetadataSchemas(); err != nil {
-		return
+// registry returns a registry of the built-in schemas with the built-in converters
+func (b *builtinSchemas) registry() *schemaRegistry {
+	keys := make(map[int16]*registryEntry)
+	for version, schema := range b.offsetCommitKeys {
+		keys[int16(version)] = &registryEntry{name: OffsetCommitKeyName, schema: schema, convertKey: convertOffsetKey}
 	}
-	if err = initGroupMetadataValueSchemas(); err != nil {
-		return
+	for version, schema := range b.groupMetadataKeys {
+		keys[int16(version)] = &registryEntry{name: GroupMetadataKeyName, schema: schema, convertKey: convertGroupMetadataKey}
 	}
-
-	GroupValueSchemas = make(map[int]*kafkaschema.Schema)
-	GroupValueSchemas[0] = GroupMetadataValueSchemaV0
-	GroupValueSchemas[1] = GroupMetadataValueSchemaV1
-	GroupValueSchemas[2] = GroupMetadataValueSchemaV2
-	GroupValueSchemas[3] = GroupMetadataValueSchemaV3
-	return
-}
-
-func initGroupMetadataValueSchemas() (err error) {
-	if GroupMetadataValueSchemaV0, err = kafkaschema.NewSchema(
-		kafkaschema.NewField(ProtocolTypeKey, kafkaschema.STRING),
-		kafkaschema.NewField(GenerationKey, kafkaschema.INT32),
-		kafkaschema.NewField(ProtocolKey, kafkaschema.NullableString),
-		kafkaschema.NewField(LeaderKey, kafkaschema.NullableString),
-		kafkaschema.NewField(MembersKey, kafkaschema.NewArrayOf(MemberMetadataV0)),
-	); err != nil {
-		return fmt.Errorf("initGroupMetadataValueSchemas %s", err)
-	}
-	if GroupMetadataValueSchemaV1, err = kafkaschema.NewSchema(
-		kafkaschema.NewField(ProtocolTypeKey, kafkaschema.STRING),
-		kafkaschema.NewField(GenerationKey, kafkaschema.INT32),
-		kafkaschema.NewField(ProtocolKey, kafkaschema.NullableString),
-		kafkaschema.NewField(LeaderKey, kafkaschema.NullableString),
-		kafkaschema.NewField(MembersKey, kafkaschema.NewArrayOf(MemberMetadataV1)),
-	); err != nil {
-		return fmt.Errorf("initGroupMetadataValueSchemas %s", err)
+	offsetValues := make(map[int16]*registryEntry)
+	for version, schema := range b.offsetValues {
+		offsetValues[int16(version)] = &registryEntry{name: OffsetCommitValueName, schema: schema, convertOffsetValue: convertOffsetValue}
 	}
-	if GroupMetadataValueSchemaV2, err = kafkaschema.NewSchema(
-		kafkaschema.NewField(ProtocolTypeKey, kafkaschema.STRING),
-		kafkaschema.NewField(GenerationKey, kafkaschema.INT32),
-		kafkaschema.NewField(ProtocolKey, kafkaschema.NullableString),
-		kafkaschema.NewField(LeaderKey, kafkaschema.NullableString),
-		kafkaschema.NewField(CurrentStateTimestampKey, kafkaschema.INT64),
-		kafkaschema.NewField(MembersKey, kafkaschema.NewArrayOf(MemberMetadataV2)),
-	); err != nil {
-		return fmt.Errorf("initGroupMetadataValueSchemas %s", err)
+	groupValues := make(map[int16]*registryEntry)
+	for version, schema := range b.groupValues {
+		groupValues[int16(version)] = &registryEntry{name: GroupMetadataValueName, schema: schema, convertGroupValue: convertGroupValue}
 	}
-	if GroupMetadataValueSchemaV3, err = kafkaschema.NewSchema(
-		kafkaschema.NewField(ProtocolTypeKey, kafkaschema.STRING),
-		kafkaschema.NewField(GenerationKey, kafkaschema.INT32),
-		kafkaschema.NewField(ProtocolKey, kafkaschema.NullableString),
-		kafkaschema.NewField(LeaderKey, kafkaschema.NullableString),
-		kafkaschema.NewField(CurrentStateTimestampKey, kafkaschema.INT64),
-		kafkaschema.NewField(MembersKey, kafkaschema.NewArrayOf(MemberMetadataV3)),
-	); err != nil {
-		return fmt.Errorf("initGroupMetadataValueSchemas %s", err)
+	return &schemaRegistry{
+		entries: map[common.BufferType]map[int16]*registryEntry{
+			common.MessageKey:  keys,
+			common.OffsetValue: offsetValues,
+			common.GroupValue:  groupValues,
+		},
+		memberMetadata:   b.memberMetadata,
+		consumerProtocol: b.consumerProtocol,
 	}
-	return
 }
 
-func initMemberMetadataSchemas() (err error) {
-	if MemberMetadataV0, err = kafkaschema.NewSchema(
-		kafkaschema.NewField(MemberIdKey, kafkaschema.STRING),
-		kafkaschema.NewField(ClientIdKey, kafkaschema.STRING),
-		kafkaschema.NewField(ClientHostKey, kafkaschema.STRING),
-		kafkaschema.NewField(SessionTimeoutKey, kafkaschema.INT32),
-		kafkaschema.NewField(SubscriptionKey, kafkaschema.BYTES),
-		kafkaschema.NewField(AssignmentKey, kafkaschema.BYTES),
-	); err != nil {
-		return fmt.Errorf("initMemberMetadataSchemas %s", err)
-	}
-	if MemberMetadataV1, err = kafkaschema.NewSchema(
-		kafkaschema.NewField(MemberIdKey, kafkaschema.STRING),
-		kafkaschema.NewField(ClientIdKey, kafkaschema.STRING),
-		kafkaschema.NewField(ClientHostKey, kafkaschema.STRING),
-		kafkaschema.NewField(RebalanceTimeoutKey, kafkaschema.INT32),
-		kafkaschema.NewField(SessionTimeoutKey, kafkaschema.INT32),
-		kafkaschema.NewField(SubscriptionKey, kafkaschema.BYTES),
-		kafkaschema.NewField(AssignmentKey, kafkaschema.BYTES),
-	); err != nil {
-		return fmt.Errorf("initMemberMetadataSchemas %s", err)
-	}
-	MemberMetadataV2 = MemberMetadataV1
-	if MemberMetadataV3, err = kafkaschema.NewSchema(
-		kafkaschema.NewField(MemberIdKey, kafkaschema.STRING),
-		kafkaschema.NewField(GroupInstanceIdKey, kafkaschema.NullableString),
-		kafkaschema.NewField(ClientIdKey, kafkaschema.STRING),
-		kafkaschema.NewField(ClientHostKey, kafkaschema.STRING),
-		kafkaschema.NewField(SessionTimeoutKey, kafkaschema.INT32),
-		kafkaschema.NewField(SubscriptionKey, kafkaschema.BYTES),
-		kafkaschema.NewField(AssignmentKey, kafkaschema.BYTES),
-	); err != nil {
-		return fmt.Errorf("initMemberMetadataSchemas %s", err)
-	}
-	return
+// setGlobals sets the deprecated schema variables of the package to the built-in schemas
+func (b *builtinSchemas) setGlobals() {
+	MessageTypeSchemas = map[int]*kafkaschema.Schema{
+		0: b.offsetCommitKeys[0],
+		1: b.offsetCommitKeys[1],
+		2: b.groupMetadataKeys[2],
+	}
+	OffsetValueSchemas = b.offsetValues
+	GroupValueSchemas = b.groupValues
+
+	OffsetCommitKeySchema = b.offsetCommitKeys[1]
+	OffsetKeyGroupField, _ = OffsetCommitKeySchema.Get(GroupKey)
+	OffsetKeyTopicField, _ = OffsetCommitKeySchema.Get(TopicKey)
+	OffsetKeyPartitionField, _ = OffsetCommitKeySchema.Get(PartitionKey)
+	GroupMetadataKeySchema = b.groupMetadataKeys[2]
+	GroupKeyGroupField, _ = GroupMetadataKeySchema.Get(GroupKey)
+
+	OffsetCommitValueSchemaV0 = OffsetValueSchemas[0]
+	OffsetCommitValueSchemaV1 = OffsetValueSchemas[1]
+	OffsetCommitValueSchemaV2 = OffsetValueSchemas[2]
+	OffsetCommitValueSchemaV3 = OffsetValueSchemas[3]
+	OffsetCommitValueSchemaV4 = OffsetValueSchemas[4]
+
+	MemberMetadataV0 = b.memberMetadata[0]
+	MemberMetadataV1 = b.memberMetadata[1]
+	MemberMetadataV2 = b.memberMetadata[2]
+	MemberMetadataV3 = b.memberMetadata[3]
+	MemberMetadataV4 = b.memberMetadata[4]
+
+	GroupMetadataValueSchemaV0 = GroupValueSchemas[0]
+	GroupMetadataValueSchemaV1 = GroupValueSchemas[1]
+	GroupMetadataValueSchemaV2 = GroupValueSchemas[2]
+	GroupMetadataValueSchemaV3 = GroupValueSchemas[3]
+	GroupMetadataValueSchemaV4 = GroupValueSchemas[4]
+
+	b.consumerProtocol.setGlobals()
 }
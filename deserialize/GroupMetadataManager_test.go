@@ -0,0 +1,398 @@
+package deserialize
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"kafka_schema/deserialize/common"
+	"kafka_schema/schema"
+	"kafka_schema/schema/buffer"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	// subscriptionV0 subscribes to topic t without user data
+	subscriptionV0 = "0000" + "00000001" + "000174" + "ffffffff"
+	// assignmentV0 assigns partition 3 of topic t without user data
+	assignmentV0 = "0000" + "00000001" + "000174" + "00000001" + "00000003" + "ffffffff"
+)
+
+// groupValueV3 returns a group metadata value V3 of group consumer with one member m1
+func groupValueV3(subscription, assignment string) string {
+	return "0003" + "0008" + hex.EncodeToString([]byte("consumer")) + "00000005" + "0005" + hex.EncodeToString([]byte("range")) +
+		"0002" + "6d31" + "0000000000000063" +
+		"00000001" + "0002" + "6d31" + "ffff" + "000163" + "000168" + "0000000a" + "0000000a" +
+		fmt.Sprintf("%08x", len(subscription)/2) + subscription +
+		fmt.Sprintf("%08x", len(assignment)/2) + assignment
+}
+
+func wrap(t *testing.T, data string) *buffer.ByteBuffer {
+	t.Helper()
+	b, err := hex.DecodeString(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Wrap(b)
+}
+
+func TestReadGroupMessageValue(t *testing.T) {
+	d, err := NewDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	group, err := d.ReadGroupMessageValue("g", wrap(t, groupValueV3(subscriptionV0, assignmentV0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	members := group.AllMemberMetadata()
+	if len(members) != 1 {
+		t.Fatalf("AllMemberMetadata() = %v, want one member", members)
+	}
+	if len(group.Warnings()) != 0 {
+		t.Errorf("Warnings() = %v, want none", group.Warnings())
+	}
+}
+
+func TestReadGroupMessageValueTruncatedSubscription(t *testing.T) {
+	truncated := "0000" + "00000001" + "0001"
+	tests := []struct {
+		name    string
+		lenient bool
+	}{
+		{name: "strict", lenient: false},
+		{name: "lenient", lenient: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDecoder()
+			if err != nil {
+				t.Fatal(err)
+			}
+			group, err := d.WithLenient(tt.lenient).ReadGroupMessageValue("g", wrap(t, groupValueV3(truncated, assignmentV0)))
+			if !tt.lenient {
+				if group != nil {
+					t.Errorf("ReadGroupMessageValue() = %v, want no group", group)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("ReadGroupMessageValue() error = %v", err)
+				}
+				if len(group.Warnings()) != 1 {
+					t.Fatalf("Warnings() = %v, want one warning", group.Warnings())
+				}
+				err = group.Warnings()[0]
+			}
+			if !errors.Is(err, kafkaschema.ErrBufferUnderflow) {
+				t.Errorf("errors.Is(%v, ErrBufferUnderflow) = false", err)
+			}
+			var de *kafkaschema.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("errors.As(%v) = false", err)
+			}
+			if de.Schema != GroupMetadataValueName || de.Version != 3 || !strings.HasPrefix(de.Path, "members[0].subscription.") {
+				t.Errorf("DecodeError = %+v, want a field of the subscription of GroupMetadataValue V3", *de)
+			}
+		})
+	}
+}
+
+func TestReadGroupMessageValueMemberMismatch(t *testing.T) {
+	member, err := kafkaschema.NewSchema(
+		kafkaschema.NewField(MemberIdKey, kafkaschema.STRING),
+		kafkaschema.NewField(ClientIdKey, kafkaschema.INT32),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := kafkaschema.NewSchema(
+		kafkaschema.NewField(ProtocolTypeKey, kafkaschema.STRING),
+		kafkaschema.NewField(GenerationKey, kafkaschema.INT32),
+		kafkaschema.NewField(ProtocolKey, kafkaschema.NullableString),
+		kafkaschema.NewField(LeaderKey, kafkaschema.NullableString),
+		kafkaschema.NewField(MembersKey, kafkaschema.NewArrayOf(member)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterGroupValue(0, value, nil); err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDecoder(UseRegistry(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := "0000" + "0001" + "63" + "00000005" + "0001" + "72" + "0002" + "6d31" + "00000001" + "0002" + "6d31" + "00000007"
+	_, err = d.ReadGroupMessageValue("g", wrap(t, data))
+	var de *kafkaschema.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("errors.As(%v) = false", err)
+	}
+	if de.Schema != GroupMetadataValueName || de.Version != 0 || de.Path != "members[0]" || de.Offset != 0 {
+		t.Errorf("DecodeError = %+v, want members[0] of GroupMetadataValue V0 at offset 0", *de)
+	}
+}
+
+func TestReadGroupMessageValueNullSubscription(t *testing.T) {
+	member, err := kafkaschema.NewSchema(
+		kafkaschema.NewField(MemberIdKey, kafkaschema.STRING),
+		kafkaschema.NewField(ClientIdKey, kafkaschema.STRING),
+		kafkaschema.NewField(ClientHostKey, kafkaschema.STRING),
+		kafkaschema.NewField(SubscriptionKey, kafkaschema.NullableBytes),
+		kafkaschema.NewField(AssignmentKey, kafkaschema.NullableBytes),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := kafkaschema.NewSchema(
+		kafkaschema.NewField(ProtocolTypeKey, kafkaschema.STRING),
+		kafkaschema.NewField(GenerationKey, kafkaschema.INT32),
+		kafkaschema.NewField(ProtocolKey, kafkaschema.NullableString),
+		kafkaschema.NewField(LeaderKey, kafkaschema.NullableString),
+		kafkaschema.NewField(MembersKey, kafkaschema.NewArrayOf(member)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterGroupValue(0, value, nil); err != nil {
+		t.Fatal(err)
+	}
+	data := "0000" + "0001" + "63" + "00000005" + "0001" + "72" + "0002" + "6d31" +
+		"00000001" + "0002" + "6d31" + "000163" + "000168" + "ffffffff" + fmt.Sprintf("%08x", len(assignmentV0)/2) + assignmentV0
+	tests := []struct {
+		name    string
+		lenient bool
+	}{
+		{name: "strict", lenient: false},
+		{name: "lenient", lenient: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDecoder(UseRegistry(r))
+			if err != nil {
+				t.Fatal(err)
+			}
+			group, err := d.WithLenient(tt.lenient).ReadGroupMessageValue("g", wrap(t, data))
+			if !tt.lenient {
+				if group != nil {
+					t.Errorf("ReadGroupMessageValue() = %v, want no group", group)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("ReadGroupMessageValue() error = %v", err)
+				}
+				if members := group.AllMemberMetadata(); len(members) != 1 || len(members[0].TopicPartitions()) != 1 {
+					t.Errorf("AllMemberMetadata() = %v, want one member with its assignment", members)
+				}
+				if len(group.Warnings()) != 1 {
+					t.Fatalf("Warnings() = %v, want one warning", group.Warnings())
+				}
+				err = group.Warnings()[0]
+			}
+			if !errors.Is(err, kafkaschema.ErrNegativeLength) {
+				t.Errorf("errors.Is(%v, ErrNegativeLength) = false", err)
+			}
+			var de *kafkaschema.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("errors.As(%v) = false", err)
+			}
+			if de.Schema != GroupMetadataValueName || de.Version != 0 || de.Path != "members[0].subscription" || de.Offset != 0 {
+				t.Errorf("DecodeError = %+v, want members[0].subscription of GroupMetadataValue V0 at offset 0", *de)
+			}
+		})
+	}
+}
+
+const (
+	// offsetValueV1 commits offset 42 with metadata md
+	offsetValueV1 = "0001" + "000000000000002a" + "0002" + "6d64" + "00000000000003e8" + "00000000000007d0"
+	// offsetValueV4Body is an offset commit value V4 of offset 42 and leader epoch 7 after the version
+	offsetValueV4Body = "000000000000002a" + "00000007" + "03" + "6d64" + "00000000000003e8"
+)
+
+func TestReadOffsetMessageValueLenient(t *testing.T) {
+	tests := []struct {
+		name        string
+		lenient     bool
+		data        string
+		wantErr     error
+		wantWarning error
+	}{
+		{name: "strict", data: offsetValueV1},
+		{name: "lenient", lenient: true, data: offsetValueV1},
+		{name: "strict trailing bytes", data: offsetValueV1 + "ff"},
+		{name: "lenient trailing bytes", lenient: true, data: offsetValueV1 + "ff", wantWarning: kafkaschema.ErrTrailingBytes},
+		{name: "strict unknown version", data: "0005" + offsetValueV4Body + "00", wantErr: kafkaschema.ErrUnknownVersion},
+		{name: "lenient unknown version", lenient: true, data: "0005" + offsetValueV4Body + "00", wantWarning: kafkaschema.ErrUnknownVersion},
+		{name: "lenient unknown older version", lenient: true, data: "ffff" + offsetValueV4Body + "00", wantErr: kafkaschema.ErrUnknownVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options []DecoderOption
+			if tt.lenient {
+				options = append(options, Lenient())
+			}
+			d, err := NewDecoder(options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, err := d.ReadOffsetMessageValue(wrap(t, tt.data))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ReadOffsetMessageValue() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadOffsetMessageValue() error = %v", err)
+			}
+			if value.Offset != 42 || value.MetaData != "md" {
+				t.Errorf("ReadOffsetMessageValue() = %+v, want offset 42 and metadata md", value)
+			}
+			if tt.wantWarning == nil {
+				if len(value.Warnings) != 0 {
+					t.Errorf("Warnings = %v, want none", value.Warnings)
+				}
+				return
+			}
+			if len(value.Warnings) != 1 || !errors.Is(value.Warnings[0], tt.wantWarning) {
+				t.Fatalf("Warnings = %v, want %v", value.Warnings, tt.wantWarning)
+			}
+			var de *kafkaschema.DecodeError
+			if !errors.As(value.Warnings[0], &de) || de.Schema != OffsetCommitValueName {
+				t.Errorf("warning %v does not name %s", value.Warnings[0], OffsetCommitValueName)
+			}
+		})
+	}
+}
+
+func TestInitGroupMetadataManager(t *testing.T) {
+	if err := InitGroupMetadataManager(); err != nil {
+		t.Fatal(err)
+	}
+	gmm := Gmm
+	if gmm == nil || OffsetCommitValueSchemaV4 == nil || GroupMetadataValueSchemaV4 == nil {
+		t.Fatal("InitGroupMetadataManager() did not set the globals")
+	}
+	if err := InitGroupMetadataManager(); err != nil {
+		t.Fatalf("second InitGroupMetadataManager() error = %v", err)
+	}
+	if Gmm != gmm {
+		t.Error("second InitGroupMetadataManager() replaced Gmm")
+	}
+}
+
+func TestDecodersSideBySide(t *testing.T) {
+	strict, err := NewDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lenient, err := NewDecoder(Lenient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := hex.DecodeString(offsetValueV1 + "ff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if value, err := strict.ReadOffsetMessageValue(buffer.Wrap(data)); err != nil || len(value.Warnings) != 0 {
+				errs <- fmt.Errorf("strict decoder = %v, %v, want no warning", value, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if value, err := lenient.ReadOffsetMessageValue(buffer.Wrap(data)); err != nil || len(value.Warnings) != 1 {
+				errs <- fmt.Errorf("lenient decoder = %v, %v, want one warning", value, err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestReadOffsetMessageValueLeaderEpoch(t *testing.T) {
+	tests := []struct {
+		name            string
+		data            string
+		wantLeaderEpoch int32
+		wantHasEpoch    bool
+	}{
+		{name: "V1 without leader epoch", data: offsetValueV1, wantLeaderEpoch: common.NoLeaderEpoch},
+		{name: "V3 without leader epoch", data: "0003" + "000000000000002a" + "ffffffff" + "0002" + "6d64" + "00000000000003e8", wantLeaderEpoch: common.NoLeaderEpoch},
+		{name: "V3", data: "0003" + "000000000000002a" + "00000007" + "0002" + "6d64" + "00000000000003e8", wantLeaderEpoch: 7, wantHasEpoch: true},
+		{name: "V4", data: "0004" + offsetValueV4Body + "00", wantLeaderEpoch: 7, wantHasEpoch: true},
+		{name: "V4 with an unknown tagged field", data: "0004" + offsetValueV4Body + "01" + "05" + "02" + "beef", wantLeaderEpoch: 7, wantHasEpoch: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDecoder()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := wrap(t, tt.data)
+			value, err := d.ReadOffsetMessageValue(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.HasRemaining() {
+				t.Errorf("ReadOffsetMessageValue() left %d bytes", b.Remaining())
+			}
+			if value.Offset != 42 || value.MetaData != "md" || value.CommitTimestamp != 1000 {
+				t.Errorf("ReadOffsetMessageValue() = %+v, want offset 42, metadata md and commit timestamp 1000", value)
+			}
+			if value.LeaderEpoch != tt.wantLeaderEpoch || value.HasLeaderEpoch() != tt.wantHasEpoch {
+				t.Errorf("LeaderEpoch, HasLeaderEpoch() = %d, %t, want %d, %t", value.LeaderEpoch, value.HasLeaderEpoch(), tt.wantLeaderEpoch, tt.wantHasEpoch)
+			}
+		})
+	}
+}
+
+func TestReadGroupMessageValueV4(t *testing.T) {
+	compact := func(s string) string {
+		return fmt.Sprintf("%02x", len(s)+1) + hex.EncodeToString([]byte(s))
+	}
+	compactBytes := func(data string) string {
+		return fmt.Sprintf("%02x", len(data)/2+1) + data
+	}
+	data := "0004" + compact("consumer") + "00000005" + compact("range") + compact("m1") + "0000000000000063" +
+		"02" + compact("m1") + "00" + compact("c") + compact("h") + "0000000a" + "0000000a" +
+		compactBytes(subscriptionV0) + compactBytes(assignmentV0) + "00" +
+		"01" + "07" + "01" + "ff"
+	d, err := NewDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	group, err := d.ReadGroupMessageValue("g", wrap(t, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(group.Warnings()) != 0 {
+		t.Errorf("Warnings() = %v, want none", group.Warnings())
+	}
+	members := group.AllMemberMetadata()
+	if len(members) != 1 || members[0].MemberID() != "m1" || members[0].ClientHost() != "h" {
+		t.Fatalf("AllMemberMetadata() = %v, want member m1 of host h", members)
+	}
+	partitions := members[0].TopicPartitions()
+	if len(partitions) != 1 || partitions[0].Topic() != "t" || partitions[0].Partition() != 3 {
+		t.Errorf("TopicPartitions() = %v, want partition 3 of topic t", partitions)
+	}
+}
@@ -0,0 +1,179 @@
+package deserialize
+
+import (
+	"fmt"
+	"kafka_schema/deserialize/common"
+	"kafka_schema/schema"
+	"sort"
+	"sync"
+)
+
+// KeyConverter converts a key read with a registered key schema to the domain
+type KeyConverter func(version int16, key *kafkaschema.Struct) (common.BaseKey, error)
+
+// OffsetValueConverter converts an offset commit value read with a registered schema to the domain
+type OffsetValueConverter func(version int16, value *kafkaschema.Struct) (*common.OffsetAndMetadata, error)
+
+// GroupValueConverter converts a group metadata value read with a registered schema to the
+// domain. The decoder reads within the limits of the value, its consumer protocol reads the
+// subscriptions and assignments of the members and the problems a lenient decoder keeps
+// going past are added as warnings of the group.
+type GroupValueConverter func(d *Decoder, groupID string, version int16, value *kafkaschema.Struct) (*common.GroupMetadata, error)
+
+// Registry holds the schemas of the keys and values of the __consumer_offsets records by
+// version, with the converters of what they read to the domain. A Registry is safe for
+// concurrent use, a Decoder created with UseRegistry reads with the versions registered
+// when it was created.
+type Registry struct {
+	mu      sync.RWMutex
+	schemas *schemaRegistry
+}
+
+// NewRegistry returns a registry of the built-in versions
+func NewRegistry() (*Registry, error) {
+	builtin, err := newBuiltinSchemas()
+	if err != nil {
+		return nil, err
+	}
+	return &Registry{schemas: builtin.registry()}, nil
+}
+
+// RegisterKey registers the key schema of the version under the name, replacing the schema
+// registered for the version if any. A nil converter converts to an offset key when the
+// schema has a topic field and to a group metadata key otherwise.
+func (r *Registry) RegisterKey(version int16, name string, schema *kafkaschema.Schema, convert KeyConverter) error {
+	if convert == nil {
+		convert = convertGroupMetadataKey
+		if schema != nil {
+			if _, err := schema.Get(TopicKey); err == nil {
+				convert = convertOffsetKey
+			}
+		}
+	}
+	return r.register(common.MessageKey, version, &registryEntry{name: name, schema: schema, convertKey: convert})
+}
+
+// RegisterOffsetValue registers the offset commit value schema of the version, replacing the
+// schema registered for the version if any. A nil converter converts with the built-in one,
+// which reads the fields by name.
+func (r *Registry) RegisterOffsetValue(version int16, schema *kafkaschema.Schema, convert OffsetValueConverter) error {
+	if convert == nil {
+		convert = convertOffsetValue
+	}
+	return r.register(common.OffsetValue, version, &registryEntry{name: OffsetCommitValueName, schema: schema, convertOffsetValue: convert})
+}
+
+// RegisterGroupValue registers the group metadata value schema of the version, replacing the
+// schema registered for the version if any. A nil converter converts with the built-in one,
+// which reads the fields by name.
+func (r *Registry) RegisterGroupValue(version int16, schema *kafkaschema.Schema, convert GroupValueConverter) error {
+	if convert == nil {
+		convert = convertGroupValue
+	}
+	return r.register(common.GroupValue, version, &registryEntry{name: GroupMetadataValueName, schema: schema, convertGroupValue: convert})
+}
+
+// KeyVersions returns the registered key versions in increasing order
+func (r *Registry) KeyVersions() []int16 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.schemas.versions(common.MessageKey)
+}
+
+// OffsetValueVersions returns the registered offset commit value versions in increasing order
+func (r *Registry) OffsetValueVersions() []int16 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.schemas.versions(common.OffsetValue)
+}
+
+// GroupValueVersions returns the registered group metadata value versions in increasing order
+func (r *Registry) GroupValueVersions() []int16 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.schemas.versions(common.GroupValue)
+}
+
+func (r *Registry) register(bt common.BufferType, version int16, entry *registryEntry) error {
+	if version < 0 {
+		return fmt.Errorf("cannot register %s: negative version %d", entry.name, version)
+	}
+	if entry.schema == nil {
+		return fmt.Errorf("cannot register %s V%d: schema is null", entry.name, version)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas.entries[bt][version] = entry
+	return nil
+}
+
+// snapshot returns a copy of the schemas registered so far
+func (r *Registry) snapshot() *schemaRegistry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.schemas.clone()
+}
+
+// registryEntry is a registered schema with the converter of the buffer type it is registered for
+type registryEntry struct {
+	name               string
+	schema             *kafkaschema.Schema
+	convertKey         KeyConverter
+	convertOffsetValue OffsetValueConverter
+	convertGroupValue  GroupValueConverter
+}
+
+// schemaRegistry holds the schemas a Decoder reads with, it is not modified once a Decoder
+// reads with it
+type schemaRegistry struct {
+	entries          map[common.BufferType]map[int16]*registryEntry
+	memberMetadata   map[int]*kafkaschema.Schema
+	consumerProtocol *consumerProtocolSchemas
+}
+
+func (r *schemaRegistry) clone() *schemaRegistry {
+	c := *r
+	c.entries = make(map[common.BufferType]map[int16]*registryEntry, len(r.entries))
+	for bt, entries := range r.entries {
+		c.entries[bt] = make(map[int16]*registryEntry, len(entries))
+		for version, entry := range entries {
+			c.entries[bt][version] = entry
+		}
+	}
+	return &c
+}
+
+// lookup returns the schema registered for the version of the buffer type
+func (r *schemaRegistry) lookup(bt common.BufferType, version int16) (*registryEntry, error) {
+	entry, ok := r.entries[bt][version]
+	if !ok {
+		switch bt {
+		case common.OffsetValue:
+			return nil, fmt.Errorf("%w of offset commit value: %v", kafkaschema.ErrUnknownVersion, version)
+		case common.GroupValue:
+			return nil, fmt.Errorf("%w of group metadata value: %v", kafkaschema.ErrUnknownVersion, version)
+		}
+		return nil, fmt.Errorf("%w of message key: %v", kafkaschema.ErrUnknownVersion, version)
+	}
+	return entry, nil
+}
+
+// latest returns the highest registered version of the buffer type, -1 when there is none
+func (r *schemaRegistry) latest(bt common.BufferType) (int16, *registryEntry) {
+	latest := int16(-1)
+	for version := range r.entries[bt] {
+		if version > latest {
+			latest = version
+		}
+	}
+	return latest, r.entries[bt][latest]
+}
+
+func (r *schemaRegistry) versions(bt common.BufferType) []int16 {
+	versions := make([]int16, 0, len(r.entries[bt]))
+	for version := range r.entries[bt] {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
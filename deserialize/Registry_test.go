@@ -0,0 +1,160 @@
+package deserialize
+
+import (
+	"errors"
+	"fmt"
+	"kafka_schema/deserialize/common"
+	"kafka_schema/schema"
+	"reflect"
+	"testing"
+)
+
+func TestRegistryVersions(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		versions func() []int16
+		want     []int16
+	}{
+		{name: "keys", versions: r.KeyVersions, want: []int16{0, 1, 2}},
+		{name: "offset values", versions: r.OffsetValueVersions, want: []int16{0, 1, 2, 3, 4}},
+		{name: "group values", versions: r.GroupValueVersions, want: []int16{0, 1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.versions(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("versions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryRegisterInvalid(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterOffsetValue(-1, r.schemas.entries[common.OffsetValue][1].schema, nil); err == nil {
+		t.Error("RegisterOffsetValue() of a negative version succeeded")
+	}
+	if err := r.RegisterGroupValue(5, nil, nil); err == nil {
+		t.Error("RegisterGroupValue() of a null schema succeeded")
+	}
+}
+
+func TestRegistryRegisterKey(t *testing.T) {
+	offsetKey, err := kafkaschema.NewSchema(
+		kafkaschema.NewField(GroupKey, kafkaschema.STRING),
+		kafkaschema.NewField(TopicKey, kafkaschema.STRING),
+		kafkaschema.NewField(PartitionKey, kafkaschema.INT32),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupKey, err := kafkaschema.NewSchema(kafkaschema.NewField(GroupKey, kafkaschema.STRING))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		schema *kafkaschema.Schema
+		data   string
+		want   common.BaseKey
+	}{
+		{name: "offset key", schema: offsetKey, data: "0003" + "000167" + "000174" + "00000001",
+			want: common.NewOffsetKey(3, common.NewGroupTopicPartition("g", common.NewTopicPartition("t", 1)))},
+		{name: "group metadata key", schema: groupKey, data: "0003" + "000167", want: common.NewGroupMetadataKey(3, "g")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRegistry()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := r.RegisterKey(3, tt.name, tt.schema, nil); err != nil {
+				t.Fatal(err)
+			}
+			d, err := NewDecoder(UseRegistry(r))
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, err := d.ReadMessageKey(wrap(t, tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(key, tt.want) {
+				t.Errorf("ReadMessageKey() = %+v, want %+v", key, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryOverride(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := d.registry.entries[common.OffsetValue][1].schema
+	convert := func(version int16, value *kafkaschema.Struct) (*common.OffsetAndMetadata, error) {
+		offset, err := value.GetInt64(OffsetKey)
+		if err != nil {
+			return nil, err
+		}
+		return common.NewOffsetAndMetadata1(offset+1, fmt.Sprintf("V%d", version), 0), nil
+	}
+	if err := r.RegisterOffsetValue(1, schema, convert); err != nil {
+		t.Fatal(err)
+	}
+	overridden, err := NewDecoder(UseRegistry(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := overridden.ReadOffsetMessageValue(wrap(t, offsetValueV1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.Offset != 43 || value.MetaData != "V1" {
+		t.Errorf("ReadOffsetMessageValue() = %+v, want the converted offset 43 and metadata V1", value)
+	}
+	if value, err = d.ReadOffsetMessageValue(wrap(t, offsetValueV1)); err != nil || value.Offset != 42 {
+		t.Errorf("ReadOffsetMessageValue() of the built-in decoder = %+v, %v, want offset 42", value, err)
+	}
+}
+
+func TestRegistrySnapshot(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := NewDecoder(UseRegistry(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := before.registry.entries[common.OffsetValue][4].schema
+	if err := r.RegisterOffsetValue(5, schema, nil); err != nil {
+		t.Fatal(err)
+	}
+	after, err := NewDecoder(UseRegistry(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := "0005" + offsetValueV4Body + "00"
+	if _, err := before.ReadOffsetMessageValue(wrap(t, data)); !errors.Is(err, kafkaschema.ErrUnknownVersion) {
+		t.Errorf("ReadOffsetMessageValue() of a decoder created before the registration error = %v, want %v", err, kafkaschema.ErrUnknownVersion)
+	}
+	value, err := after.ReadOffsetMessageValue(wrap(t, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.Offset != 42 || value.LeaderEpoch != 7 || len(value.Warnings) != 0 {
+		t.Errorf("ReadOffsetMessageValue() = %+v, want offset 42 and leader epoch 7", value)
+	}
+}
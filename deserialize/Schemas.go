@@ -0,0 +1,62 @@
+package deserialize
+
+import (
+	"fmt"
+	"kafka_schema/deserialize/common"
+	"kafka_schema/schema"
+	"sort"
+)
+
+// NamedSchema is a schema of the package with the name and the version it is documented
+// under, Version is -1 for the schemas without version
+type NamedSchema struct {
+	Name    string
+	Version int
+	Schema  *kafkaschema.Schema
+}
+
+// Title returns the name followed by the version if any, e.g. "GroupMetadataValue V3"
+func (n NamedSchema) Title() string {
+	if n.Version < 0 {
+		return n.Name
+	}
+	return fmt.Sprintf("%s V%d", n.Name, n.Version)
+}
+
+// RegisteredSchemas returns the schemas of a new Decoder, see Decoder.RegisteredSchemas
+func RegisteredSchemas() ([]NamedSchema, error) {
+	d, err := NewDecoder()
+	if err != nil {
+		return nil, err
+	}
+	return d.RegisteredSchemas(), nil
+}
+
+// RegisteredSchemas returns the schemas of the __consumer_offsets records and of the consumer
+// protocol the decoder reads with, keys first then values, each by version
+func (d *Decoder) RegisteredSchemas() []NamedSchema {
+	r := d.registry
+	schemas := make([]NamedSchema, 0)
+	for _, bt := range []common.BufferType{common.MessageKey, common.OffsetValue, common.GroupValue} {
+		for _, version := range r.versions(bt) {
+			entry := r.entries[bt][version]
+			schemas = append(schemas, NamedSchema{Name: entry.name, Version: int(version), Schema: entry.schema})
+		}
+	}
+	versions := make([]int, 0, len(r.memberMetadata))
+	for version := range r.memberMetadata {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	for _, version := range versions {
+		schemas = append(schemas, NamedSchema{Name: MemberMetadataName, Version: version, Schema: r.memberMetadata[version]})
+	}
+	schemas = append(schemas,
+		NamedSchema{Name: "ConsumerProtocolHeader", Version: -1, Schema: r.consumerProtocol.header},
+		NamedSchema{Name: SubscriptionName, Version: 0, Schema: r.consumerProtocol.subscriptionV0},
+		NamedSchema{Name: SubscriptionName, Version: 1, Schema: r.consumerProtocol.subscriptionV1},
+		NamedSchema{Name: AssignmentName, Version: 0, Schema: r.consumerProtocol.assignmentV0},
+		NamedSchema{Name: "ConsumerProtocolTopicAssignment", Version: 0, Schema: r.consumerProtocol.topicAssignmentV0},
+	)
+	return schemas
+}
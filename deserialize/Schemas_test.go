@@ -0,0 +1,72 @@
+package deserialize
+
+import (
+	"kafka_schema/schema"
+	"reflect"
+	"testing"
+)
+
+func TestRegisteredSchemas(t *testing.T) {
+	d, err := NewDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, s := range d.RegisteredSchemas() {
+		if s.Schema == nil {
+			t.Errorf("%s has no schema", s.Title())
+		}
+		titles = append(titles, s.Title())
+	}
+	want := []string{
+		"OffsetCommitKey V0", "OffsetCommitKey V1", "GroupMetadataKey V2",
+		"OffsetCommitValue V0", "OffsetCommitValue V1", "OffsetCommitValue V2", "OffsetCommitValue V3", "OffsetCommitValue V4",
+		"GroupMetadataValue V0", "GroupMetadataValue V1", "GroupMetadataValue V2", "GroupMetadataValue V3", "GroupMetadataValue V4",
+		"MemberMetadata V0", "MemberMetadata V1", "MemberMetadata V2", "MemberMetadata V3", "MemberMetadata V4",
+		"ConsumerProtocolHeader", "ConsumerProtocolSubscription V0", "ConsumerProtocolSubscription V1",
+		"ConsumerProtocolAssignment V0", "ConsumerProtocolTopicAssignment V0",
+	}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("RegisteredSchemas() = %v, want %v", titles, want)
+	}
+}
+
+func TestRegisteredSchemasMemberMetadataVersions(t *testing.T) {
+	d, err := NewDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err := kafkaschema.NewSchema(kafkaschema.NewField(MemberIdKey, kafkaschema.STRING))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := d.registry.clone()
+	registry.memberMetadata = map[int]*kafkaschema.Schema{5: member, 1: member}
+	d = &Decoder{registry: registry}
+
+	var versions []int
+	for _, s := range d.RegisteredSchemas() {
+		if s.Name != MemberMetadataName {
+			continue
+		}
+		if s.Schema != member {
+			t.Errorf("%s = %v, want %v", s.Title(), s.Schema, member)
+		}
+		versions = append(versions, s.Version)
+	}
+	if want := []int{1, 5}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("MemberMetadata versions = %v, want %v", versions, want)
+	}
+}
+
+func TestMemberMetadataDiff(t *testing.T) {
+	d, err := NewDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := kafkaschema.Diff(d.registry.memberMetadata[2], d.registry.memberMetadata[3])
+	want := "incompatible\nfield 'group_instance_id' added at position 1 with type NULLABLE_STRING"
+	if got := diff.String(); got != want {
+		t.Errorf("Diff(MemberMetadata V2, V3) =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -10,6 +10,7 @@ type Subscription struct {
 	userData        *buffer2.ByteBuffer
 	ownedPartitions []*common.TopicPartition
 	groupInstanceId string
+	warnings        []error
 }
 
 func NewSubscription(topics []string, userData *buffer2.ByteBuffer, ownedPartitions []*common.TopicPartition) *Subscription {
@@ -23,3 +24,8 @@ func NewSubscription(topics []string, userData *buffer2.ByteBuffer, ownedPartiti
 func (s *Subscription) Topics() []string {
 	return s.topics
 }
+
+// Warnings returns the problems a lenient decoding of the subscription kept going past
+func (s *Subscription) Warnings() []error {
+	return s.warnings
+}
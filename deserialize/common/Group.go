@@ -20,6 +20,7 @@ type GroupMetadata struct {
 	leaderID              string
 	currentStateTimestamp int64
 	members               map[string]*MemberMetadata
+	warnings              []error
 }
 
 func NewGroupMetadata(groupID string, initialState GroupState, time int64) *GroupMetadata {
@@ -53,6 +54,21 @@ func LoadGroup(
 	return groupMetadata
 }
 
+// Warnings returns the problems a lenient decoding of the group kept going past, they are
+// *kafkaschema.DecodeError locating the problem in the group metadata value
+func (g *GroupMetadata) Warnings() []error {
+	return g.warnings
+}
+
+func (g *GroupMetadata) AddWarnings(warnings ...error) {
+	g.warnings = append(g.warnings, warnings...)
+}
+
+// SetWarnings replaces the warnings of the group
+func (g *GroupMetadata) SetWarnings(warnings []error) {
+	g.warnings = warnings
+}
+
 func (g *GroupMetadata) add(member *MemberMetadata) {
 	if g.members == nil {
 		g.members = make(map[string]*MemberMetadata)
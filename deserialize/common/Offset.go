@@ -1,19 +1,25 @@
 package common
 
-type Int interface{}
+// NoLeaderEpoch is the leader epoch of an offset committed without one
+const NoLeaderEpoch = int32(-1)
 
 type OffsetAndMetadata struct {
-	Offset          int64
-	leaderEpoch     Int
+	Offset int64
+	// LeaderEpoch is the leader epoch of the committed offset from OffsetCommitValue V3 on,
+	// NoLeaderEpoch when unknown
+	LeaderEpoch     int32
 	MetaData        string
 	CommitTimestamp int64
 	ExpireTimestamp int64
+	// Warnings are the problems a lenient decoding kept going past, they are
+	// *kafkaschema.DecodeError locating the problem in the offset commit value
+	Warnings []error
 }
 
 func NewOffsetAndMetadata1(offset int64, metadata string, commitTimestamp int64) *OffsetAndMetadata {
 	return &OffsetAndMetadata{
 		Offset:          offset,
-		leaderEpoch:     nil,
+		LeaderEpoch:     NoLeaderEpoch,
 		MetaData:        metadata,
 		CommitTimestamp: commitTimestamp,
 		ExpireTimestamp: 0,
@@ -23,19 +29,24 @@ func NewOffsetAndMetadata1(offset int64, metadata string, commitTimestamp int64)
 func NewOffsetAndMetadata2(offset int64, metadata string, commitTimestamp int64, expireTimestamp int64) *OffsetAndMetadata {
 	return &OffsetAndMetadata{
 		Offset:          offset,
-		leaderEpoch:     nil,
+		LeaderEpoch:     NoLeaderEpoch,
 		MetaData:        metadata,
 		CommitTimestamp: commitTimestamp,
 		ExpireTimestamp: expireTimestamp,
 	}
 }
 
-func NewOffsetAndMetadata3(offset int64, leaderEpoch Int, metadata string, commitTimestamp int64) *OffsetAndMetadata {
+func NewOffsetAndMetadata3(offset int64, leaderEpoch int32, metadata string, commitTimestamp int64) *OffsetAndMetadata {
 	return &OffsetAndMetadata{
 		Offset:          offset,
-		leaderEpoch:     leaderEpoch,
+		LeaderEpoch:     leaderEpoch,
 		MetaData:        metadata,
 		CommitTimestamp: commitTimestamp,
 		ExpireTimestamp: 0,
 	}
 }
+
+// HasLeaderEpoch tells whether the offset was committed with a leader epoch
+func (o *OffsetAndMetadata) HasLeaderEpoch() bool {
+	return o.LeaderEpoch != NoLeaderEpoch
+}
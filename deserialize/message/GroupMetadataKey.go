@@ -0,0 +1,60 @@
+// Code generated by messagegen. DO NOT EDIT.
+
+package message
+
+import (
+	"fmt"
+	"kafka_schema/schema/buffer"
+)
+
+const (
+	GroupMetadataKeyLowestSupportedVersion  = int16(2)
+	GroupMetadataKeyHighestSupportedVersion = int16(2)
+)
+
+// GroupMetadataKey is the GroupMetadataKey message, valid for versions 2
+type GroupMetadataKey struct {
+	Group string
+}
+
+// NewGroupMetadataKey returns a new GroupMetadataKey whose fields are set to their default values
+func NewGroupMetadataKey() *GroupMetadataKey {
+	return &GroupMetadataKey{}
+}
+
+// Read reads the GroupMetadataKey of the given version from the buffer
+func (m *GroupMetadataKey) Read(buf *buffer.ByteBuffer, version int16) error {
+	if version < GroupMetadataKeyLowestSupportedVersion || version > GroupMetadataKeyHighestSupportedVersion {
+		return fmt.Errorf("unsupported GroupMetadataKey version %d", version)
+	}
+	var err error
+	flexible := false
+	if m.Group, err = readString(buf, flexible); err != nil {
+		return fmt.Errorf("error reading field 'group': %v", err)
+	}
+	return err
+}
+
+// Write writes the GroupMetadataKey with the given version into the buffer
+func (m *GroupMetadataKey) Write(buf *buffer.ByteBuffer, version int16) error {
+	if version < GroupMetadataKeyLowestSupportedVersion || version > GroupMetadataKeyHighestSupportedVersion {
+		return fmt.Errorf("unsupported GroupMetadataKey version %d", version)
+	}
+	var err error
+	flexible := false
+	if err = writeString(buf, m.Group, flexible); err != nil {
+		return fmt.Errorf("error writing field 'group': %v", err)
+	}
+	return err
+}
+
+// Size returns the size in bytes of the GroupMetadataKey written with the given version
+func (m *GroupMetadataKey) Size(version int16) (int, error) {
+	if version < GroupMetadataKeyLowestSupportedVersion || version > GroupMetadataKeyHighestSupportedVersion {
+		return 0, fmt.Errorf("unsupported GroupMetadataKey version %d", version)
+	}
+	size := 0
+	flexible := false
+	size += sizeOfString(m.Group, flexible)
+	return size, nil
+}
@@ -0,0 +1,311 @@
+// Code generated by messagegen. DO NOT EDIT.
+
+package message
+
+import (
+	"fmt"
+	kafkaschema "kafka_schema/schema"
+	"kafka_schema/schema/buffer"
+)
+
+const (
+	GroupMetadataValueLowestSupportedVersion  = int16(0)
+	GroupMetadataValueHighestSupportedVersion = int16(4)
+)
+
+// GroupMetadataValue is the GroupMetadataValue message, valid for versions 0-4
+type GroupMetadataValue struct {
+	// The protocol type of the group.
+	ProtocolType string
+	// The generation of the group.
+	Generation int32
+	// The selected protocol, null when the group is empty.
+	Protocol *string
+	// The member id of the leader, null when the group is empty.
+	Leader *string
+	// The time of the last state change of the group.
+	CurrentStateTimestamp int64
+	// The members of the group.
+	Members []MemberMetadata
+	// UnknownTaggedFields are the tagged fields of the flexible versions unknown to this struct
+	UnknownTaggedFields []kafkaschema.RawTaggedField
+}
+
+// NewGroupMetadataValue returns a new GroupMetadataValue whose fields are set to their default values
+func NewGroupMetadataValue() *GroupMetadataValue {
+	return &GroupMetadataValue{
+		Protocol:              stringPtr(""),
+		Leader:                stringPtr(""),
+		CurrentStateTimestamp: -1,
+		Members:               []MemberMetadata{},
+	}
+}
+
+// Read reads the GroupMetadataValue of the given version from the buffer
+func (m *GroupMetadataValue) Read(buf *buffer.ByteBuffer, version int16) error {
+	if version < GroupMetadataValueLowestSupportedVersion || version > GroupMetadataValueHighestSupportedVersion {
+		return fmt.Errorf("unsupported GroupMetadataValue version %d", version)
+	}
+	var err error
+	flexible := version == 4
+	if m.ProtocolType, err = readString(buf, flexible); err != nil {
+		return fmt.Errorf("error reading field 'protocol_type': %v", err)
+	}
+	if m.Generation, err = readInt32(buf); err != nil {
+		return fmt.Errorf("error reading field 'generation': %v", err)
+	}
+	if m.Protocol, err = readNullableString(buf, flexible, true); err != nil {
+		return fmt.Errorf("error reading field 'protocol': %v", err)
+	}
+	if m.Leader, err = readNullableString(buf, flexible, true); err != nil {
+		return fmt.Errorf("error reading field 'leader': %v", err)
+	}
+	if version >= 2 {
+		if m.CurrentStateTimestamp, err = readInt64(buf); err != nil {
+			return fmt.Errorf("error reading field 'current_state_timestamp': %v", err)
+		}
+	} else {
+		m.CurrentStateTimestamp = -1
+	}
+	{
+		var n int
+		if n, err = readArrayLength(buf, flexible, false); err != nil {
+			return fmt.Errorf("error reading field 'members': %v", err)
+		}
+		if n < 0 {
+			m.Members = nil
+		} else {
+			m.Members = make([]MemberMetadata, n)
+			for i := range m.Members {
+				if err = m.Members[i].Read(buf, version); err != nil {
+					return fmt.Errorf("error reading field 'members': %v", err)
+				}
+			}
+		}
+	}
+	if flexible {
+		taggedFields, err := readTaggedFields(buf)
+		if err != nil {
+			return err
+		}
+		m.UnknownTaggedFields = taggedFields
+	}
+	return err
+}
+
+// Write writes the GroupMetadataValue with the given version into the buffer
+func (m *GroupMetadataValue) Write(buf *buffer.ByteBuffer, version int16) error {
+	if version < GroupMetadataValueLowestSupportedVersion || version > GroupMetadataValueHighestSupportedVersion {
+		return fmt.Errorf("unsupported GroupMetadataValue version %d", version)
+	}
+	var err error
+	flexible := version == 4
+	if err = writeString(buf, m.ProtocolType, flexible); err != nil {
+		return fmt.Errorf("error writing field 'protocol_type': %v", err)
+	}
+	if err = writeInt32(buf, m.Generation); err != nil {
+		return fmt.Errorf("error writing field 'generation': %v", err)
+	}
+	if err = writeNullableString(buf, m.Protocol, flexible, true); err != nil {
+		return fmt.Errorf("error writing field 'protocol': %v", err)
+	}
+	if err = writeNullableString(buf, m.Leader, flexible, true); err != nil {
+		return fmt.Errorf("error writing field 'leader': %v", err)
+	}
+	if version >= 2 {
+		if err = writeInt64(buf, m.CurrentStateTimestamp); err != nil {
+			return fmt.Errorf("error writing field 'current_state_timestamp': %v", err)
+		}
+	}
+	if err = writeArrayLength(buf, len(m.Members), m.Members == nil, flexible, false); err != nil {
+		return fmt.Errorf("error writing field 'members': %v", err)
+	}
+	for i := range m.Members {
+		if err = m.Members[i].Write(buf, version); err != nil {
+			return fmt.Errorf("error writing field 'members': %v", err)
+		}
+	}
+	if flexible {
+		taggedFields := make([]kafkaschema.RawTaggedField, 0, len(m.UnknownTaggedFields))
+		taggedFields = append(taggedFields, m.UnknownTaggedFields...)
+		if err = writeTaggedFields(buf, taggedFields); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// Size returns the size in bytes of the GroupMetadataValue written with the given version
+func (m *GroupMetadataValue) Size(version int16) (int, error) {
+	if version < GroupMetadataValueLowestSupportedVersion || version > GroupMetadataValueHighestSupportedVersion {
+		return 0, fmt.Errorf("unsupported GroupMetadataValue version %d", version)
+	}
+	size := 0
+	flexible := version == 4
+	size += sizeOfString(m.ProtocolType, flexible)
+	size += 4
+	size += sizeOfNullableString(m.Protocol, flexible)
+	size += sizeOfNullableString(m.Leader, flexible)
+	if version >= 2 {
+		size += 8
+	}
+	size += sizeOfArrayLength(len(m.Members), flexible)
+	for i := range m.Members {
+		elementSize, err := m.Members[i].Size(version)
+		if err != nil {
+			return 0, err
+		}
+		size += elementSize
+	}
+	if flexible {
+		numTaggedFields := len(m.UnknownTaggedFields)
+		for _, raw := range m.UnknownTaggedFields {
+			size += sizeOfTaggedField(raw.Tag, len(raw.Data))
+		}
+		size += buffer.SizeOfUnsignedVarint(int32(numTaggedFields))
+	}
+	return size, nil
+}
+
+type MemberMetadata struct {
+	// The member id.
+	MemberId string
+	// The static member instance id.
+	GroupInstanceId *string
+	// The client id of the member.
+	ClientId string
+	// The host of the member.
+	ClientHost string
+	// The rebalance timeout of the member.
+	RebalanceTimeout int32
+	// The session timeout of the member.
+	SessionTimeout int32
+	// The serialized consumer protocol subscription.
+	Subscription []byte
+	// The serialized consumer protocol assignment.
+	Assignment []byte
+	// UnknownTaggedFields are the tagged fields of the flexible versions unknown to this struct
+	UnknownTaggedFields []kafkaschema.RawTaggedField
+}
+
+// NewMemberMetadata returns a new MemberMetadata whose fields are set to their default values
+func NewMemberMetadata() *MemberMetadata {
+	return &MemberMetadata{
+		Subscription: []byte{},
+		Assignment:   []byte{},
+	}
+}
+
+// Read reads the MemberMetadata of the given version from the buffer
+func (m *MemberMetadata) Read(buf *buffer.ByteBuffer, version int16) error {
+	var err error
+	flexible := version == 4
+	if m.MemberId, err = readString(buf, flexible); err != nil {
+		return fmt.Errorf("error reading field 'member_id': %v", err)
+	}
+	if version >= 3 {
+		if m.GroupInstanceId, err = readNullableString(buf, flexible, version >= 3); err != nil {
+			return fmt.Errorf("error reading field 'group_instance_id': %v", err)
+		}
+	} else {
+		m.GroupInstanceId = nil
+	}
+	if m.ClientId, err = readString(buf, flexible); err != nil {
+		return fmt.Errorf("error reading field 'client_id': %v", err)
+	}
+	if m.ClientHost, err = readString(buf, flexible); err != nil {
+		return fmt.Errorf("error reading field 'client_host': %v", err)
+	}
+	if version >= 1 {
+		if m.RebalanceTimeout, err = readInt32(buf); err != nil {
+			return fmt.Errorf("error reading field 'rebalance_timeout': %v", err)
+		}
+	} else {
+		m.RebalanceTimeout = 0
+	}
+	if m.SessionTimeout, err = readInt32(buf); err != nil {
+		return fmt.Errorf("error reading field 'session_timeout': %v", err)
+	}
+	if m.Subscription, err = readBytes(buf, flexible, false); err != nil {
+		return fmt.Errorf("error reading field 'subscription': %v", err)
+	}
+	if m.Assignment, err = readBytes(buf, flexible, false); err != nil {
+		return fmt.Errorf("error reading field 'assignment': %v", err)
+	}
+	if flexible {
+		taggedFields, err := readTaggedFields(buf)
+		if err != nil {
+			return err
+		}
+		m.UnknownTaggedFields = taggedFields
+	}
+	return err
+}
+
+// Write writes the MemberMetadata with the given version into the buffer
+func (m *MemberMetadata) Write(buf *buffer.ByteBuffer, version int16) error {
+	var err error
+	flexible := version == 4
+	if err = writeString(buf, m.MemberId, flexible); err != nil {
+		return fmt.Errorf("error writing field 'member_id': %v", err)
+	}
+	if version >= 3 {
+		if err = writeNullableString(buf, m.GroupInstanceId, flexible, version >= 3); err != nil {
+			return fmt.Errorf("error writing field 'group_instance_id': %v", err)
+		}
+	}
+	if err = writeString(buf, m.ClientId, flexible); err != nil {
+		return fmt.Errorf("error writing field 'client_id': %v", err)
+	}
+	if err = writeString(buf, m.ClientHost, flexible); err != nil {
+		return fmt.Errorf("error writing field 'client_host': %v", err)
+	}
+	if version >= 1 {
+		if err = writeInt32(buf, m.RebalanceTimeout); err != nil {
+			return fmt.Errorf("error writing field 'rebalance_timeout': %v", err)
+		}
+	}
+	if err = writeInt32(buf, m.SessionTimeout); err != nil {
+		return fmt.Errorf("error writing field 'session_timeout': %v", err)
+	}
+	if err = writeBytes(buf, m.Subscription, flexible, false); err != nil {
+		return fmt.Errorf("error writing field 'subscription': %v", err)
+	}
+	if err = writeBytes(buf, m.Assignment, flexible, false); err != nil {
+		return fmt.Errorf("error writing field 'assignment': %v", err)
+	}
+	if flexible {
+		taggedFields := make([]kafkaschema.RawTaggedField, 0, len(m.UnknownTaggedFields))
+		taggedFields = append(taggedFields, m.UnknownTaggedFields...)
+		if err = writeTaggedFields(buf, taggedFields); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// Size returns the size in bytes of the MemberMetadata written with the given version
+func (m *MemberMetadata) Size(version int16) (int, error) {
+	size := 0
+	flexible := version == 4
+	size += sizeOfString(m.MemberId, flexible)
+	if version >= 3 {
+		size += sizeOfNullableString(m.GroupInstanceId, flexible)
+	}
+	size += sizeOfString(m.ClientId, flexible)
+	size += sizeOfString(m.ClientHost, flexible)
+	if version >= 1 {
+		size += 4
+	}
+	size += 4
+	size += sizeOfBytes(m.Subscription, flexible)
+	size += sizeOfBytes(m.Assignment, flexible)
+	if flexible {
+		numTaggedFields := len(m.UnknownTaggedFields)
+		for _, raw := range m.UnknownTaggedFields {
+			size += sizeOfTaggedField(raw.Tag, len(raw.Data))
+		}
+		size += buffer.SizeOfUnsignedVarint(int32(numTaggedFields))
+	}
+	return size, nil
+}
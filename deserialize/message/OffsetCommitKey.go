@@ -0,0 +1,76 @@
+// Code generated by messagegen. DO NOT EDIT.
+
+package message
+
+import (
+	"fmt"
+	"kafka_schema/schema/buffer"
+)
+
+const (
+	OffsetCommitKeyLowestSupportedVersion  = int16(0)
+	OffsetCommitKeyHighestSupportedVersion = int16(1)
+)
+
+// OffsetCommitKey is the OffsetCommitKey message, valid for versions 0-1
+type OffsetCommitKey struct {
+	Group     string
+	Topic     string
+	Partition int32
+}
+
+// NewOffsetCommitKey returns a new OffsetCommitKey whose fields are set to their default values
+func NewOffsetCommitKey() *OffsetCommitKey {
+	return &OffsetCommitKey{}
+}
+
+// Read reads the OffsetCommitKey of the given version from the buffer
+func (m *OffsetCommitKey) Read(buf *buffer.ByteBuffer, version int16) error {
+	if version < OffsetCommitKeyLowestSupportedVersion || version > OffsetCommitKeyHighestSupportedVersion {
+		return fmt.Errorf("unsupported OffsetCommitKey version %d", version)
+	}
+	var err error
+	flexible := false
+	if m.Group, err = readString(buf, flexible); err != nil {
+		return fmt.Errorf("error reading field 'group': %v", err)
+	}
+	if m.Topic, err = readString(buf, flexible); err != nil {
+		return fmt.Errorf("error reading field 'topic': %v", err)
+	}
+	if m.Partition, err = readInt32(buf); err != nil {
+		return fmt.Errorf("error reading field 'partition': %v", err)
+	}
+	return err
+}
+
+// Write writes the OffsetCommitKey with the given version into the buffer
+func (m *OffsetCommitKey) Write(buf *buffer.ByteBuffer, version int16) error {
+	if version < OffsetCommitKeyLowestSupportedVersion || version > OffsetCommitKeyHighestSupportedVersion {
+		return fmt.Errorf("unsupported OffsetCommitKey version %d", version)
+	}
+	var err error
+	flexible := false
+	if err = writeString(buf, m.Group, flexible); err != nil {
+		return fmt.Errorf("error writing field 'group': %v", err)
+	}
+	if err = writeString(buf, m.Topic, flexible); err != nil {
+		return fmt.Errorf("error writing field 'topic': %v", err)
+	}
+	if err = writeInt32(buf, m.Partition); err != nil {
+		return fmt.Errorf("error writing field 'partition': %v", err)
+	}
+	return err
+}
+
+// Size returns the size in bytes of the OffsetCommitKey written with the given version
+func (m *OffsetCommitKey) Size(version int16) (int, error) {
+	if version < OffsetCommitKeyLowestSupportedVersion || version > OffsetCommitKeyHighestSupportedVersion {
+		return 0, fmt.Errorf("unsupported OffsetCommitKey version %d", version)
+	}
+	size := 0
+	flexible := false
+	size += sizeOfString(m.Group, flexible)
+	size += sizeOfString(m.Topic, flexible)
+	size += 4
+	return size, nil
+}
@@ -0,0 +1,140 @@
+// Code generated by messagegen. DO NOT EDIT.
+
+package message
+
+import (
+	"fmt"
+	kafkaschema "kafka_schema/schema"
+	"kafka_schema/schema/buffer"
+)
+
+const (
+	OffsetCommitValueLowestSupportedVersion  = int16(0)
+	OffsetCommitValueHighestSupportedVersion = int16(4)
+)
+
+// OffsetCommitValue is the OffsetCommitValue message, valid for versions 0-4
+type OffsetCommitValue struct {
+	// The committed offset.
+	Offset int64
+	// The leader epoch of the last consumed record.
+	LeaderEpoch int32
+	// Associated metadata.
+	Metadata string
+	// The time the offset was committed.
+	CommitTimestamp int64
+	// The time the offset expires, -1 if it uses the broker retention.
+	ExpireTimestamp int64
+	// UnknownTaggedFields are the tagged fields of the flexible versions unknown to this struct
+	UnknownTaggedFields []kafkaschema.RawTaggedField
+}
+
+// NewOffsetCommitValue returns a new OffsetCommitValue whose fields are set to their default values
+func NewOffsetCommitValue() *OffsetCommitValue {
+	return &OffsetCommitValue{
+		LeaderEpoch:     -1,
+		ExpireTimestamp: -1,
+	}
+}
+
+// Read reads the OffsetCommitValue of the given version from the buffer
+func (m *OffsetCommitValue) Read(buf *buffer.ByteBuffer, version int16) error {
+	if version < OffsetCommitValueLowestSupportedVersion || version > OffsetCommitValueHighestSupportedVersion {
+		return fmt.Errorf("unsupported OffsetCommitValue version %d", version)
+	}
+	var err error
+	flexible := version == 4
+	if m.Offset, err = readInt64(buf); err != nil {
+		return fmt.Errorf("error reading field 'offset': %v", err)
+	}
+	if version >= 3 {
+		if m.LeaderEpoch, err = readInt32(buf); err != nil {
+			return fmt.Errorf("error reading field 'leader_epoch': %v", err)
+		}
+	} else {
+		m.LeaderEpoch = -1
+	}
+	if m.Metadata, err = readString(buf, flexible); err != nil {
+		return fmt.Errorf("error reading field 'metadata': %v", err)
+	}
+	if m.CommitTimestamp, err = readInt64(buf); err != nil {
+		return fmt.Errorf("error reading field 'commit_timestamp': %v", err)
+	}
+	if version == 1 {
+		if m.ExpireTimestamp, err = readInt64(buf); err != nil {
+			return fmt.Errorf("error reading field 'expire_timestamp': %v", err)
+		}
+	} else {
+		m.ExpireTimestamp = -1
+	}
+	if flexible {
+		taggedFields, err := readTaggedFields(buf)
+		if err != nil {
+			return err
+		}
+		m.UnknownTaggedFields = taggedFields
+	}
+	return err
+}
+
+// Write writes the OffsetCommitValue with the given version into the buffer
+func (m *OffsetCommitValue) Write(buf *buffer.ByteBuffer, version int16) error {
+	if version < OffsetCommitValueLowestSupportedVersion || version > OffsetCommitValueHighestSupportedVersion {
+		return fmt.Errorf("unsupported OffsetCommitValue version %d", version)
+	}
+	var err error
+	flexible := version == 4
+	if err = writeInt64(buf, m.Offset); err != nil {
+		return fmt.Errorf("error writing field 'offset': %v", err)
+	}
+	if version >= 3 {
+		if err = writeInt32(buf, m.LeaderEpoch); err != nil {
+			return fmt.Errorf("error writing field 'leader_epoch': %v", err)
+		}
+	}
+	if err = writeString(buf, m.Metadata, flexible); err != nil {
+		return fmt.Errorf("error writing field 'metadata': %v", err)
+	}
+	if err = writeInt64(buf, m.CommitTimestamp); err != nil {
+		return fmt.Errorf("error writing field 'commit_timestamp': %v", err)
+	}
+	if version == 1 {
+		if err = writeInt64(buf, m.ExpireTimestamp); err != nil {
+			return fmt.Errorf("error writing field 'expire_timestamp': %v", err)
+		}
+	}
+	if flexible {
+		taggedFields := make([]kafkaschema.RawTaggedField, 0, len(m.UnknownTaggedFields))
+		taggedFields = append(taggedFields, m.UnknownTaggedFields...)
+		if err = writeTaggedFields(buf, taggedFields); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// Size returns the size in bytes of the OffsetCommitValue written with the given version
+func (m *OffsetCommitValue) Size(version int16) (int, error) {
+	if version < OffsetCommitValueLowestSupportedVersion || version > OffsetCommitValueHighestSupportedVersion {
+		return 0, fmt.Errorf("unsupported OffsetCommitValue version %d", version)
+	}
+	size := 0
+	flexible := version == 4
+	size += 8
+	if version >= 3 {
+		size += 4
+	}
+	size += sizeOfString(m.Metadata, flexible)
+	size += 8
+	if version == 1 {
+		size += 8
+	}
+	if flexible {
+		numTaggedFields := len(m.UnknownTaggedFields)
+		for _, raw := range m.UnknownTaggedFields {
+			size += sizeOfTaggedField(raw.Tag, len(raw.Data))
+		}
+		size += buffer.SizeOfUnsignedVarint(int32(numTaggedFields))
+	}
+	return size, nil
+}
@@ -0,0 +1,102 @@
+package message
+
+import (
+	"bytes"
+	"encoding/hex"
+	kafkaschema "kafka_schema/schema"
+	"kafka_schema/schema/buffer"
+	"reflect"
+	"testing"
+)
+
+func TestOffsetCommitValueRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int16
+		data    string
+		want    *OffsetCommitValue
+	}{
+		{
+			name:    "V1",
+			version: 1,
+			data:    "000000000000002a" + "00016d" + "0000000000000064" + "00000000000000c8",
+			want:    &OffsetCommitValue{Offset: 42, LeaderEpoch: -1, Metadata: "m", CommitTimestamp: 100, ExpireTimestamp: 200},
+		},
+		{
+			name:    "V3",
+			version: 3,
+			data:    "000000000000002a" + "00000007" + "00016d" + "0000000000000064",
+			want:    &OffsetCommitValue{Offset: 42, LeaderEpoch: 7, Metadata: "m", CommitTimestamp: 100, ExpireTimestamp: -1},
+		},
+		{
+			name:    "V4 without tagged fields",
+			version: 4,
+			data:    "000000000000002a" + "00000007" + "026d" + "0000000000000064" + "00",
+			want:    &OffsetCommitValue{Offset: 42, LeaderEpoch: 7, Metadata: "m", CommitTimestamp: 100, ExpireTimestamp: -1},
+		},
+		{
+			name:    "V4 with tagged fields",
+			version: 4,
+			data:    "000000000000002a" + "00000007" + "026d" + "0000000000000064" + "02" + "0502aabb" + "0700",
+			want: &OffsetCommitValue{Offset: 42, LeaderEpoch: 7, Metadata: "m", CommitTimestamp: 100, ExpireTimestamp: -1,
+				UnknownTaggedFields: []kafkaschema.RawTaggedField{{Tag: 5, Data: []byte{0xaa, 0xbb}}, {Tag: 7, Data: []byte{}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			buf := buffer.Wrap(data)
+			got := NewOffsetCommitValue()
+			if err := got.Read(buf, tt.version); err != nil {
+				t.Fatal(err)
+			}
+			if buf.Remaining() != 0 {
+				t.Errorf("Read() left %d bytes", buf.Remaining())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() = %+v, want %+v", got, tt.want)
+			}
+
+			size, err := got.Size(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size != len(data) {
+				t.Errorf("Size() = %d, want %d", size, len(data))
+			}
+			out := buffer.AllocateExpandable(size)
+			if err := got.Write(out, tt.version); err != nil {
+				t.Fatal(err)
+			}
+			out.Flip()
+			if !bytes.Equal(out.Bytes(), data) {
+				t.Errorf("Write() = %x, want %x", out.Bytes(), data)
+			}
+		})
+	}
+}
+
+func TestOffsetCommitValueReadInvalidTaggedFields(t *testing.T) {
+	body := "000000000000002a" + "00000007" + "026d" + "0000000000000064"
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "size beyond the buffer", data: body + "01" + "0503aabb"},
+		{name: "out-of-order tags", data: body + "02" + "0501aa" + "0301bb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := NewOffsetCommitValue().Read(buffer.Wrap(data), 4); err == nil {
+				t.Error("Read() succeeded")
+			}
+		})
+	}
+}
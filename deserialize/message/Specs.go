@@ -0,0 +1,9 @@
+package message
+
+import "embed"
+
+// Specs holds the message specifications of this directory, the deserialize package builds
+// its built-in schemas from them
+//
+//go:embed *.json
+var Specs embed.FS
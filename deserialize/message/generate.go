@@ -0,0 +1,5 @@
+// Package message holds the records of the __consumer_offsets topic as typed structs,
+// generated from the Kafka message specifications of this directory.
+package message
+
+//go:generate go run kafka_schema/cmd/messagegen -package message OffsetCommitKey.json OffsetCommitValue.json GroupMetadataKey.json GroupMetadataValue.json
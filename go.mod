@@ -1,3 +1,3 @@
 module kafka_schema
 
-go 1.16
+go 1.18
@@ -0,0 +1,272 @@
+package kafkaschema
+
+import (
+	"fmt"
+	"kafka_schema/schema/buffer"
+	"reflect"
+)
+
+// BindingTag is the struct tag naming the schema field bound to a Go struct field,
+// e.g. `kafka:"commit_timestamp"`. Fields without the tag, or tagged "-", are not bound.
+const BindingTag = "kafka"
+
+var (
+	structType     = reflect.TypeOf(&Struct{})
+	byteBufferType = reflect.TypeOf(&buffer.ByteBuffer{})
+	byteSliceType  = reflect.TypeOf([]byte{})
+)
+
+// Unmarshal reads a Struct of the schema from the buffer and binds it into v, which must be
+// a pointer to a Go struct. See Struct.Bind for the mapping of the values.
+func Unmarshal(schema *Schema, buf *buffer.ByteBuffer, v interface{}) error {
+	return UnmarshalWithOptions(schema, buf, v, nil)
+}
+
+// UnmarshalWithOptions is Unmarshal reading within the limits of the options
+func UnmarshalWithOptions(schema *Schema, buf *buffer.ByteBuffer, v interface{}, opts *DecodeOptions) error {
+	o, err := schema.Read(buf, opts)
+	if err != nil {
+		return err
+	}
+	return o.(*Struct).Bind(v)
+}
+
+// Marshal serializes the Go struct v, or a pointer to it, with the schema. See
+// NewStructFromValue for the mapping of the values.
+func Marshal(schema *Schema, v interface{}) (*buffer.ByteBuffer, error) {
+	ks, err := NewStructFromValue(schema, v)
+	if err != nil {
+		return nil, err
+	}
+	return Serialize(schema, ks)
+}
+
+// Bind copies the values of the struct into the fields of the Go struct pointed by v whose
+// kafka tag names a field of the schema, the other fields are left untouched so that a
+// single Go type can be bound to every version of a schema. Nested structs are bound to Go
+// structs, arrays to slices, bytes to []byte or *buffer.ByteBuffer and integers to any
+// integer type they fit in. Nullable values are bound to pointers, nil for null.
+func (ks *Struct) Bind(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot bind to %T, a non nil pointer to a struct is required", v)
+	}
+	return ks.bind(rv.Elem())
+}
+
+func (ks *Struct) bind(dst reflect.Value) error {
+	for i := 0; i < dst.NumField(); i++ {
+		name, ok := bindingName(dst.Type().Field(i))
+		if !ok {
+			continue
+		}
+		field, ok := ks.schema.fieldsByName[name]
+		if !ok {
+			continue
+		}
+		value, err := ks.GetField(field)
+		if err != nil {
+			return err
+		}
+		if err = bindValue(dst.Field(i), value); err != nil {
+			return fmt.Errorf("cannot bind field '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+func bindValue(dst reflect.Value, value interface{}) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if dst.Kind() == reflect.Ptr && dst.Type() != structType && dst.Type() != byteBufferType {
+		elem := reflect.New(dst.Type().Elem())
+		if err := bindValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+	switch v := value.(type) {
+	case *Struct:
+		if dst.Kind() == reflect.Struct {
+			return v.bind(dst)
+		}
+	case []interface{}:
+		if dst.Kind() == reflect.Slice && dst.Type() != byteSliceType {
+			slice := reflect.MakeSlice(dst.Type(), len(v), len(v))
+			for i, obj := range v {
+				if err := bindValue(slice.Index(i), obj); err != nil {
+					return fmt.Errorf("element %d: %w", i, err)
+				}
+			}
+			dst.Set(slice)
+			return nil
+		}
+	case *buffer.ByteBuffer:
+		if dst.Type() == byteSliceType {
+			dst.SetBytes(v.Bytes())
+			return nil
+		}
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Type().AssignableTo(dst.Type()) {
+		dst.Set(rv)
+		return nil
+	}
+	if converted, ok := convertInteger(rv, dst.Type()); ok {
+		dst.Set(converted)
+		return nil
+	}
+	return fmt.Errorf("%w: %T is not assignable to %s", ErrTypeMismatch, value, dst.Type())
+}
+
+// NewStructFromValue returns a Struct of the schema holding the values of the fields of
+// the Go struct v, or a pointer to it, bound with the kafka tag. It is the inverse of Bind,
+// the schema fields without a Go field get their default value.
+func NewStructFromValue(schema *Schema, v interface{}) (*Struct, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot convert %T to a Struct, a struct is required", v)
+	}
+	return structFromValue(schema, rv)
+}
+
+func structFromValue(schema *Schema, src reflect.Value) (*Struct, error) {
+	ks := NewStruct1(schema)
+	for i := 0; i < src.NumField(); i++ {
+		name, ok := bindingName(src.Type().Field(i))
+		if !ok {
+			continue
+		}
+		field, ok := schema.fieldsByName[name]
+		if !ok {
+			continue
+		}
+		value, err := valueOf(field.def.t, src.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for field '%s': %v", name, err)
+		}
+		ks.setValue(field, value)
+	}
+	return ks, nil
+}
+
+func valueOf(t Type, src reflect.Value) (interface{}, error) {
+	if src.Kind() == reflect.Ptr && src.Type() != structType && src.Type() != byteBufferType {
+		if src.IsNil() {
+			return t.Validate(nil)
+		}
+		src = src.Elem()
+	}
+	var value interface{}
+	switch tt := t.(type) {
+	case *Schema:
+		if src.Kind() == reflect.Struct {
+			return structFromValue(tt, src)
+		}
+	case *ArrayOf:
+		return arrayFromValue(tt.t, tt.nullable, src)
+	case *CompactArrayOf:
+		return arrayFromValue(tt.t, tt.nullable, src)
+	case *bytes, *nullableBytes, *compactBytes, *compactNullableBytes:
+		if src.Type() == byteSliceType {
+			if src.IsNil() && t.isNullable() {
+				return nil, nil
+			}
+			value = buffer.NewByteBuffer(src.Bytes())
+		}
+	case *i8:
+		value = integerOf(src, reflect.TypeOf(int8(0)))
+	case *i16:
+		value = integerOf(src, reflect.TypeOf(int16(0)))
+	case *i32, *varint, *unsignedVarint:
+		value = integerOf(src, reflect.TypeOf(int32(0)))
+	case *i64, *varlong:
+		value = integerOf(src, reflect.TypeOf(int64(0)))
+	case *u16:
+		value = integerOf(src, reflect.TypeOf(uint16(0)))
+	case *u32:
+		value = integerOf(src, reflect.TypeOf(uint32(0)))
+	}
+	if value == nil {
+		value = src.Interface()
+	}
+	return t.Validate(value)
+}
+
+func arrayFromValue(t Type, nullable bool, src reflect.Value) (interface{}, error) {
+	if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%s is not a slice", src.Type())
+	}
+	if src.Kind() == reflect.Slice && src.IsNil() && nullable {
+		return nil, nil
+	}
+	objs := make([]interface{}, src.Len())
+	for i := range objs {
+		obj, err := valueOf(t, src.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %v", i, err)
+		}
+		objs[i] = obj
+	}
+	return objs, nil
+}
+
+// integerOf converts an integer to the type t, it returns nil when the value is not an
+// integer or does not fit in t so that the error is reported by the validation
+func integerOf(src reflect.Value, t reflect.Type) interface{} {
+	if converted, ok := convertInteger(src, t); ok {
+		return converted.Interface()
+	}
+	return nil
+}
+
+func convertInteger(src reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	dst := reflect.New(t).Elem()
+	switch {
+	case isSigned(src.Kind()) && isSigned(t.Kind()):
+		if dst.OverflowInt(src.Int()) {
+			return dst, false
+		}
+		dst.SetInt(src.Int())
+	case isSigned(src.Kind()) && isUnsigned(t.Kind()):
+		if src.Int() < 0 || dst.OverflowUint(uint64(src.Int())) {
+			return dst, false
+		}
+		dst.SetUint(uint64(src.Int()))
+	case isUnsigned(src.Kind()) && isUnsigned(t.Kind()):
+		if dst.OverflowUint(src.Uint()) {
+			return dst, false
+		}
+		dst.SetUint(src.Uint())
+	case isUnsigned(src.Kind()) && isSigned(t.Kind()):
+		if src.Uint() > 1<<63-1 || dst.OverflowInt(int64(src.Uint())) {
+			return dst, false
+		}
+		dst.SetInt(int64(src.Uint()))
+	default:
+		return dst, false
+	}
+	return dst, true
+}
+
+func isSigned(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUnsigned(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func bindingName(f reflect.StructField) (string, bool) {
+	name, ok := f.Tag.Lookup(BindingTag)
+	if !ok || name == "" || name == "-" || f.PkgPath != "" {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,145 @@
+package kafkaschema
+
+import (
+	"encoding/hex"
+	"errors"
+	"kafka_schema/schema/buffer"
+	"reflect"
+	"testing"
+)
+
+type bindingMember struct {
+	Id         string  `kafka:"id"`
+	InstanceId *string `kafka:"instance_id"`
+}
+
+type bindingGroup struct {
+	Name       string          `kafka:"name"`
+	Generation int             `kafka:"generation"`
+	Data       []byte          `kafka:"data"`
+	Members    []bindingMember `kafka:"members"`
+	Leader     *bindingMember  `kafka:"leader"`
+	Ignored    string
+	Skipped    string `kafka:"-"`
+}
+
+func newBindingSchema(t *testing.T) *Schema {
+	t.Helper()
+	member, err := NewSchema(
+		NewField("id", STRING),
+		NewField("instance_id", NullableString),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := NewSchema(
+		NewField("name", STRING),
+		NewField("generation", INT16),
+		NewField("data", NullableBytes),
+		NewField("members", NewArrayOf(member)),
+		NewField("leader", member),
+		NewField1("epoch", INT32, "", int32(-1)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sch
+}
+
+func TestMarshalAndUnmarshal(t *testing.T) {
+	sch := newBindingSchema(t)
+	instance := "i1"
+	group := bindingGroup{
+		Name:       "g",
+		Generation: 5,
+		Data:       []byte{0xaa},
+		Members:    []bindingMember{{Id: "m1", InstanceId: &instance}, {Id: "m2"}},
+		Leader:     &bindingMember{Id: "m1", InstanceId: &instance},
+		Ignored:    "ignored",
+		Skipped:    "skipped",
+	}
+	want := "0001" + "67" + "0005" + "00000001" + "aa" +
+		"00000002" + "0002" + "6d31" + "0002" + "6931" + "0002" + "6d32" + "ffff" +
+		"0002" + "6d31" + "0002" + "6931" + "ffffffff"
+	buf, err := Marshal(sch, &group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(buf.Bytes()); got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+
+	var got bindingGroup
+	if err := Unmarshal(sch, buf, &got); err != nil {
+		t.Fatal(err)
+	}
+	group.Ignored, group.Skipped = "", ""
+	if !reflect.DeepEqual(got, group) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, group)
+	}
+}
+
+func TestBindNullValues(t *testing.T) {
+	sch := newBindingSchema(t)
+	data, _ := hex.DecodeString("0001" + "67" + "0005" + "ffffffff" + "00000000" + "0002" + "6d31" + "ffff" + "00000007")
+	group := bindingGroup{Data: []byte{1}, Members: []bindingMember{{Id: "old"}}}
+	if err := Unmarshal(sch, buffer.Wrap(data), &group); err != nil {
+		t.Fatal(err)
+	}
+	if group.Data != nil || len(group.Members) != 0 || group.Leader == nil || group.Leader.InstanceId != nil {
+		t.Errorf("Unmarshal() = %+v, want null data, no members and a leader without instance id", group)
+	}
+}
+
+func TestBindInvalid(t *testing.T) {
+	sch := newBindingSchema(t)
+	ks := NewStruct1(sch)
+	if err := ks.Set("name", "g"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Set("generation", int16(300)); err != nil {
+		t.Fatal(err)
+	}
+	var group bindingGroup
+	if err := ks.Bind(group); err == nil {
+		t.Error("Bind() to a struct value succeeded")
+	}
+	var narrow struct {
+		Generation int8 `kafka:"generation"`
+	}
+	if err := ks.Bind(&narrow); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Bind() of 300 to an int8 = %v, want %v", err, ErrTypeMismatch)
+	}
+	var mismatch struct {
+		Name int `kafka:"name"`
+	}
+	if err := ks.Bind(&mismatch); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Bind() of a string to an int = %v, want %v", err, ErrTypeMismatch)
+	}
+}
+
+func TestNewStructFromValueInvalid(t *testing.T) {
+	sch := newBindingSchema(t)
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "not a struct", value: "g"},
+		{name: "overflow", value: struct {
+			Generation int `kafka:"generation"`
+		}{Generation: 1 << 16}},
+		{name: "mismatch", value: struct {
+			Name int `kafka:"name"`
+		}{Name: 1}},
+		{name: "null member", value: struct {
+			Leader *bindingMember `kafka:"leader"`
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ks, err := NewStructFromValue(sch, tt.value); err == nil {
+				t.Errorf("NewStructFromValue() = %v, want an error", ks)
+			}
+		})
+	}
+}
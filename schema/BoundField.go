@@ -2,6 +2,7 @@ package kafkaschema
 
 import "fmt"
 
+// BoundField is a field bound to its position in a schema
 type BoundField struct {
 	def    *Field
 	index  int
@@ -16,6 +17,40 @@ func NewBoundField(def *Field, schema *Schema, index int) *BoundField {
 	}
 }
 
+func (bf *BoundField) Field() *Field {
+	return bf.def
+}
+
+func (bf *BoundField) Name() string {
+	return bf.def.name
+}
+
+func (bf *BoundField) Type() Type {
+	return bf.def.t
+}
+
+// Index returns the position of the field in its schema
+func (bf *BoundField) Index() int {
+	return bf.index
+}
+
+func (bf *BoundField) Schema() *Schema {
+	return bf.schema
+}
+
+func (bf *BoundField) Doc() string {
+	return bf.def.docString
+}
+
+// Default returns the default value of the field and false if the field has no default
+func (bf *BoundField) Default() (interface{}, bool) {
+	return bf.def.Default()
+}
+
+func (bf *BoundField) HasDefault() bool {
+	return bf.def.hasDefaultValue
+}
+
 func (bf *BoundField) String() string {
-	return fmt.Sprintf("%s:%s", bf.def.name, bf.def.t.String())
+	return fmt.Sprintf("%s:%s", bf.def.name, TypeName(bf.def.t))
 }
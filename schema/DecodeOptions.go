@@ -0,0 +1,87 @@
+package kafkaschema
+
+import (
+	"fmt"
+)
+
+// allocation sizes accounted for the values of a decoding, an interface{} holds two words
+const interfaceSize = 16
+
+// DecodeOptions are the limits of a decoding, a zero limit is no limit and a nil
+// *DecodeOptions decodes without limits. A decoding exceeding a limit fails with an error
+// wrapping ErrLimitExceeded. The options can be shared, every decoding keeps its own state.
+type DecodeOptions struct {
+	// MaxArrayLength is the maximum number of elements of an array or tagged fields section
+	MaxArrayLength int
+	// MaxBytesLength is the maximum length in bytes of a string or bytes value
+	MaxBytesLength int
+	// MaxDepth is the maximum nesting depth of structs, the top-level struct has depth 1
+	MaxDepth int
+	// MaxAllocation is the maximum number of bytes allocated for the decoded values
+	MaxAllocation int
+
+	state *decodeState
+}
+
+type decodeState struct {
+	depth     int
+	allocated int
+}
+
+// Begin returns the options of a new decoding, the reads given the returned options share
+// its depth and allocation budget. Reads given options which did not begin a decoding begin
+// their own.
+func (opts *DecodeOptions) Begin() *DecodeOptions {
+	if opts == nil {
+		return nil
+	}
+	if opts.state != nil {
+		return opts
+	}
+	begun := *opts
+	begun.state = &decodeState{}
+	return &begun
+}
+
+func (opts *DecodeOptions) checkArrayLength(length int) error {
+	if opts == nil || opts.MaxArrayLength <= 0 || length <= opts.MaxArrayLength {
+		return nil
+	}
+	return fmt.Errorf("%w: array length %d is larger than the maximum of %d", ErrLimitExceeded, length, opts.MaxArrayLength)
+}
+
+func (opts *DecodeOptions) checkBytesLength(length int) error {
+	if opts == nil || opts.MaxBytesLength <= 0 || length <= opts.MaxBytesLength {
+		return nil
+	}
+	return fmt.Errorf("%w: length %d is larger than the maximum of %d", ErrLimitExceeded, length, opts.MaxBytesLength)
+}
+
+// enter accounts for a nested struct, every successful enter is followed by a leave
+func (opts *DecodeOptions) enter() error {
+	if opts == nil {
+		return nil
+	}
+	if opts.MaxDepth > 0 && opts.state.depth >= opts.MaxDepth {
+		return fmt.Errorf("%w: nesting depth is larger than the maximum of %d", ErrLimitExceeded, opts.MaxDepth)
+	}
+	opts.state.depth++
+	return nil
+}
+
+func (opts *DecodeOptions) leave() {
+	if opts != nil {
+		opts.state.depth--
+	}
+}
+
+func (opts *DecodeOptions) allocate(size int) error {
+	if opts == nil {
+		return nil
+	}
+	opts.state.allocated += size
+	if opts.MaxAllocation > 0 && opts.state.allocated > opts.MaxAllocation {
+		return fmt.Errorf("%w: %d bytes allocated, the maximum is %d", ErrLimitExceeded, opts.state.allocated, opts.MaxAllocation)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package kafkaschema
+
+import (
+	"encoding/hex"
+	"errors"
+	"kafka_schema/schema/buffer"
+	"testing"
+)
+
+func TestDecodeOptionsLimits(t *testing.T) {
+	inner, err := NewSchema(NewField("id", INT32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	middle, err := NewSchema(NewField("inner", inner))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outer, err := NewSchema(NewField("middle", middle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		t       Type
+		opts    *DecodeOptions
+		data    string
+		wantErr error
+	}{
+		{name: "array within the length", t: NewArrayOf(INT32), opts: &DecodeOptions{MaxArrayLength: 2}, data: "00000002" + "00000001" + "00000002"},
+		{name: "array over the length", t: NewArrayOf(INT32), opts: &DecodeOptions{MaxArrayLength: 10}, data: "0000000b", wantErr: ErrLimitExceeded},
+		{name: "array claiming the largest length", t: NewArrayOf(INT32), opts: &DecodeOptions{MaxArrayLength: 10}, data: "7fffffff", wantErr: ErrLimitExceeded},
+		{name: "array claiming the largest length without limit", t: NewArrayOf(INT32), data: "7fffffff", wantErr: ErrBufferUnderflow},
+		{name: "compact array over the length", t: NewCompactArrayOf(INT32), opts: &DecodeOptions{MaxArrayLength: 10}, data: "0c", wantErr: ErrLimitExceeded},
+		{name: "tagged fields over the length", t: NewTaggedFields(nil), opts: &DecodeOptions{MaxArrayLength: 10}, data: "0b", wantErr: ErrLimitExceeded},
+		{name: "bytes within the length", t: BYTES, opts: &DecodeOptions{MaxBytesLength: 2}, data: "00000002" + "0102"},
+		{name: "bytes over the length", t: BYTES, opts: &DecodeOptions{MaxBytesLength: 10}, data: "7fffffff", wantErr: ErrLimitExceeded},
+		{name: "compact bytes over the length", t: CompactBytes, opts: &DecodeOptions{MaxBytesLength: 10}, data: "0c", wantErr: ErrLimitExceeded},
+		{name: "string over the length", t: STRING, opts: &DecodeOptions{MaxBytesLength: 10}, data: "7fff", wantErr: ErrLimitExceeded},
+		{name: "compact string over the length", t: CompactString, opts: &DecodeOptions{MaxBytesLength: 10}, data: "0c", wantErr: ErrLimitExceeded},
+		{name: "structs within the depth", t: outer, opts: &DecodeOptions{MaxDepth: 3}, data: "00000001"},
+		{name: "structs over the depth", t: outer, opts: &DecodeOptions{MaxDepth: 2}, data: "00000001", wantErr: ErrLimitExceeded},
+		{name: "array within the allocation", t: NewArrayOf(INT32), opts: &DecodeOptions{MaxAllocation: 2 * interfaceSize}, data: "00000002" + "00000001" + "00000002"},
+		{name: "array over the allocation", t: NewArrayOf(INT32), opts: &DecodeOptions{MaxAllocation: 2 * interfaceSize}, data: "00000003" + "00000001" + "00000002" + "00000003", wantErr: ErrLimitExceeded},
+		{name: "structs over the allocation", t: NewArrayOf(inner), opts: &DecodeOptions{MaxAllocation: 3 * interfaceSize}, data: "00000002" + "00000001" + "00000002", wantErr: ErrLimitExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = tt.t.Read(buffer.Wrap(data), tt.opts)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Read() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeOptionsShared(t *testing.T) {
+	opts := &DecodeOptions{MaxAllocation: 2 * interfaceSize}
+	data, _ := hex.DecodeString("00000002" + "00000001" + "00000002")
+	for i := 0; i < 3; i++ {
+		if _, err := NewArrayOf(INT32).Read(buffer.Wrap(data), opts); err != nil {
+			t.Fatalf("Read() %d of the shared options error = %v", i, err)
+		}
+	}
+}
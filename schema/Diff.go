@@ -0,0 +1,294 @@
+package kafkaschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeKind is the kind of difference between a field of two schemas
+type ChangeKind int
+
+const (
+	FieldAdded ChangeKind = iota
+	FieldRemoved
+	FieldMoved
+	FieldRetyped
+	NullabilityChanged
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case FieldAdded:
+		return "added"
+	case FieldRemoved:
+		return "removed"
+	case FieldMoved:
+		return "moved"
+	case FieldRetyped:
+		return "retyped"
+	case NullabilityChanged:
+		return "nullability changed"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+}
+
+// Change is a difference between a field of the old schema and the field of the same name
+// in the new schema. Path names the field like Walk does, e.g. "members[].rebalance_timeout".
+// The index and type of the side missing the field are -1 and nil.
+type Change struct {
+	Kind     ChangeKind
+	Path     string
+	OldIndex int
+	NewIndex int
+	OldType  Type
+	NewType  Type
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case FieldAdded:
+		return fmt.Sprintf("field '%s' added at position %d with type %s", c.Path, c.NewIndex, TypeName(c.NewType))
+	case FieldRemoved:
+		return fmt.Sprintf("field '%s' removed from position %d", c.Path, c.OldIndex)
+	case FieldMoved:
+		return fmt.Sprintf("field '%s' moved from position %d to %d", c.Path, c.OldIndex, c.NewIndex)
+	default:
+		return fmt.Sprintf("field '%s' %s from %s to %s", c.Path, c.Kind, TypeName(c.OldType), TypeName(c.NewType))
+	}
+}
+
+// Compatibility tells whether the data written with a schema can be read with another
+type Compatibility int
+
+const (
+	// Compatible schemas read the data written with each other
+	Compatible Compatibility = iota
+	// BackwardCompatible is a new schema reading the data written with the old schema only
+	BackwardCompatible
+	// ForwardCompatible is an old schema reading the data written with the new schema only
+	ForwardCompatible
+	Incompatible
+)
+
+func (c Compatibility) String() string {
+	switch c {
+	case Compatible:
+		return "compatible"
+	case BackwardCompatible:
+		return "backward compatible"
+	case ForwardCompatible:
+		return "forward compatible"
+	case Incompatible:
+		return "incompatible"
+	default:
+		return fmt.Sprintf("Compatibility(%d)", int(c))
+	}
+}
+
+// SchemaDiff is the result of Diff
+type SchemaDiff struct {
+	Changes []Change
+	// ReadsOld is true if the new schema reads any data written with the old schema
+	ReadsOld bool
+	// ReadsNew is true if the old schema reads any data written with the new schema
+	ReadsNew bool
+}
+
+// Compatibility classifies the diff from ReadsOld and ReadsNew
+func (d *SchemaDiff) Compatibility() Compatibility {
+	switch {
+	case d.ReadsOld && d.ReadsNew:
+		return Compatible
+	case d.ReadsOld:
+		return BackwardCompatible
+	case d.ReadsNew:
+		return ForwardCompatible
+	default:
+		return Incompatible
+	}
+}
+
+func (d *SchemaDiff) String() string {
+	lines := make([]string, 0, len(d.Changes)+1)
+	lines = append(lines, d.Compatibility().String())
+	for _, c := range d.Changes {
+		lines = append(lines, c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff compares the fields of the old schema with the ones of the updated schema by name,
+// nested structs and arrays of structs included, and checks whether the data written with
+// one can be read with the other. The schemas are positional so that a field added,
+// removed, moved or retyped changes the encoding, while a field becoming nullable only
+// does in the direction where a null can be read by a non nullable type.
+func Diff(old, updated *Schema) *SchemaDiff {
+	d := &SchemaDiff{
+		ReadsOld: readable(old, updated),
+		ReadsNew: readable(updated, old),
+	}
+	d.Changes = diffFields("", fieldsOf(old), fieldsOf(updated))
+	return d
+}
+
+// diffField is a field of a schema or of a tagged fields section
+type diffField struct {
+	name  string
+	index int
+	t     Type
+}
+
+func fieldsOf(t Type) []diffField {
+	var fields []diffField
+	switch tt := t.(type) {
+	case *Schema:
+		for _, f := range tt.fields {
+			fields = append(fields, diffField{name: f.def.name, index: f.index, t: f.def.t})
+		}
+	case *TaggedFields:
+		for _, tag := range tt.Tags() {
+			f := tt.fields[tag]
+			fields = append(fields, diffField{name: f.name, index: tag, t: f.t})
+		}
+	}
+	return fields
+}
+
+func diffFields(path string, old, updated []diffField) []Change {
+	var changes []Change
+	newByName := make(map[string]diffField, len(updated))
+	for _, f := range updated {
+		newByName[f.name] = f
+	}
+	oldByName := make(map[string]diffField, len(old))
+	var oldCommon, newCommon []string
+	for _, f := range old {
+		oldByName[f.name] = f
+		if _, ok := newByName[f.name]; ok {
+			oldCommon = append(oldCommon, f.name)
+		} else {
+			changes = append(changes, Change{Kind: FieldRemoved, Path: join(path, f.name), OldIndex: f.index, NewIndex: -1, OldType: f.t})
+		}
+	}
+	for _, f := range updated {
+		if _, ok := oldByName[f.name]; ok {
+			newCommon = append(newCommon, f.name)
+		} else {
+			changes = append(changes, Change{Kind: FieldAdded, Path: join(path, f.name), OldIndex: -1, NewIndex: f.index, NewType: f.t})
+		}
+	}
+	// a field is moved when its position among the fields of both schemas changes
+	for i, name := range oldCommon {
+		o, n := oldByName[name], newByName[name]
+		fieldPath := join(path, name)
+		if newCommon[i] != name {
+			changes = append(changes, Change{Kind: FieldMoved, Path: fieldPath, OldIndex: o.index, NewIndex: n.index, OldType: o.t, NewType: n.t})
+		}
+		changes = append(changes, diffTypes(fieldPath, o, n)...)
+	}
+	return changes
+}
+
+func diffTypes(path string, o, n diffField) []Change {
+	oldElement, oldArray := element(o.t)
+	newElement, newArray := element(n.t)
+	if oldArray && newArray && arrayKind(o.t) == arrayKind(n.t) {
+		changes := nullabilityChanges(path, o, n)
+		prev := diffField{name: o.name, index: o.index, t: oldElement}
+		next := diffField{name: n.name, index: n.index, t: newElement}
+		return append(changes, diffTypes(path+"[]", prev, next)...)
+	}
+	_, oldStruct := o.t.(*Schema)
+	_, newStruct := n.t.(*Schema)
+	_, oldTagged := o.t.(*TaggedFields)
+	_, newTagged := n.t.(*TaggedFields)
+	if oldStruct && newStruct || oldTagged && newTagged {
+		return diffFields(path, fieldsOf(o.t), fieldsOf(n.t))
+	}
+	if encoding(o.t) != encoding(n.t) {
+		return []Change{{Kind: FieldRetyped, Path: path, OldIndex: o.index, NewIndex: n.index, OldType: o.t, NewType: n.t}}
+	}
+	return nullabilityChanges(path, o, n)
+}
+
+func nullabilityChanges(path string, o, n diffField) []Change {
+	if o.t.isNullable() == n.t.isNullable() {
+		return nil
+	}
+	return []Change{{Kind: NullabilityChanged, Path: path, OldIndex: o.index, NewIndex: n.index, OldType: o.t, NewType: n.t}}
+}
+
+// readable returns true if the reader type reads any data written with the writer type
+func readable(writer, reader Type) bool {
+	switch w := writer.(type) {
+	case *Schema:
+		r, ok := reader.(*Schema)
+		if !ok || len(w.fields) != len(r.fields) {
+			return false
+		}
+		for i := range w.fields {
+			if !readable(w.fields[i].def.t, r.fields[i].def.t) {
+				return false
+			}
+		}
+		return true
+	case *TaggedFields:
+		r, ok := reader.(*TaggedFields)
+		if !ok {
+			return false
+		}
+		// the tags unknown to the reader are kept as raw tagged fields
+		for tag, f := range w.fields {
+			if rf, ok := r.fields[tag]; ok && !readable(f.t, rf.t) {
+				return false
+			}
+		}
+		return true
+	}
+	if writer.isNullable() && !reader.isNullable() {
+		return false
+	}
+	writerElement, writerArray := element(writer)
+	readerElement, readerArray := element(reader)
+	if writerArray || readerArray {
+		return writerArray && readerArray && arrayKind(writer) == arrayKind(reader) && readable(writerElement, readerElement)
+	}
+	return encoding(writer) == encoding(reader)
+}
+
+func element(t Type) (Type, bool) {
+	switch tt := t.(type) {
+	case *ArrayOf:
+		return tt.t, true
+	case *CompactArrayOf:
+		return tt.t, true
+	default:
+		return nil, false
+	}
+}
+
+func arrayKind(t Type) string {
+	if _, ok := t.(*CompactArrayOf); ok {
+		return "COMPACT_ARRAY"
+	}
+	return "ARRAY"
+}
+
+// encoding returns the name of the encoding of a type, the nullable types share the
+// encoding of their non nullable type
+func encoding(t Type) string {
+	name := TypeName(t)
+	switch name {
+	case "NULLABLE_STRING":
+		return "STRING"
+	case "NULLABLE_BYTES":
+		return "BYTES"
+	case "COMPACT_NULLABLE_STRING":
+		return "COMPACT_STRING"
+	case "COMPACT_NULLABLE_BYTES":
+		return "COMPACT_BYTES"
+	default:
+		return name
+	}
+}
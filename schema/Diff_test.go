@@ -0,0 +1,62 @@
+package kafkaschema
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	schema := func(fields ...*Field) *Schema {
+		sch, err := NewSchema(fields...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sch
+	}
+	member := schema(NewField("id", STRING))
+	tests := []struct {
+		name          string
+		old, updated  *Schema
+		compatibility Compatibility
+		want          string
+	}{
+		{name: "same fields in other instances", compatibility: Compatible,
+			old:     schema(NewField("name", STRING), NewField("members", NewArrayOf(member))),
+			updated: schema(NewField("name", STRING), NewField("members", NewArrayOf(schema(NewField("id", STRING))))),
+			want:    "compatible"},
+		{name: "field becoming nullable", compatibility: BackwardCompatible,
+			old:     schema(NewField("name", STRING)),
+			updated: schema(NewField("name", NullableString)),
+			want:    "backward compatible\nfield 'name' nullability changed from STRING to NULLABLE_STRING"},
+		{name: "field becoming non nullable", compatibility: ForwardCompatible,
+			old:     schema(NewField("name", NullableString)),
+			updated: schema(NewField("name", STRING)),
+			want:    "forward compatible\nfield 'name' nullability changed from NULLABLE_STRING to STRING"},
+		{name: "field removed", compatibility: Incompatible,
+			old:     schema(NewField("name", STRING), NewField("epoch", INT32)),
+			updated: schema(NewField("name", STRING)),
+			want:    "incompatible\nfield 'epoch' removed from position 1"},
+		{name: "fields of the same type moved", compatibility: Compatible,
+			old:     schema(NewField("name", STRING), NewField("host", STRING)),
+			updated: schema(NewField("host", STRING), NewField("name", STRING)),
+			want:    "compatible\nfield 'name' moved from position 0 to 1\nfield 'host' moved from position 1 to 0"},
+		{name: "fields of other types moved", compatibility: Incompatible,
+			old:     schema(NewField("name", STRING), NewField("epoch", INT32)),
+			updated: schema(NewField("epoch", INT32), NewField("name", STRING)),
+			want:    "incompatible\nfield 'name' moved from position 0 to 1\nfield 'epoch' moved from position 1 to 0"},
+		{name: "nested field retyped", compatibility: Incompatible,
+			old:     schema(NewField("members", NewArrayOf(member))),
+			updated: schema(NewField("members", NewArrayOf(schema(NewField("id", INT32))))),
+			want:    "incompatible\nfield 'members[].id' retyped from STRING to INT32"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Diff(tt.old, tt.updated)
+			if d.Compatibility() != tt.compatibility {
+				t.Errorf("Compatibility() = %s, want %s", d.Compatibility(), tt.compatibility)
+			}
+			if got := d.String(); got != tt.want {
+				t.Errorf("String() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,17 +3,32 @@ package kafkaschema
 import (
 	"fmt"
 	"kafka_schema/schema/buffer"
+	"math"
 	"strconv"
 )
 
 var (
+	BOOLEAN        = new(boolean)
+	INT8           = new(i8)
+	UINT16         = new(u16)
+	UINT32         = new(u32)
+	FLOAT64        = new(f64)
+	UUID           = &uuid{}
 	INT16          = new(i16)
 	INT32          = new(i32)
 	INT64          = new(i64)
+	VARINT         = new(varint)
+	VARLONG        = new(varlong)
+	UnsignedVarint = new(unsignedVarint)
 	STRING         = new(s)
 	NullableString = new(nullableString)
 	BYTES          = &bytes{}
 	NullableBytes  = &nullableBytes{}
+
+	CompactString         = new(compactString)
+	CompactNullableString = new(compactNullableString)
+	CompactBytes          = &compactBytes{}
+	CompactNullableBytes  = &compactNullableBytes{}
 )
 
 type DocumentedType interface {
@@ -21,9 +36,260 @@ type DocumentedType interface {
 	TypeName() string
 }
 
+type boolean bool
+
+func (b boolean) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	value, err := buffer.Get()
+	if err != nil {
+		return nil, err
+	}
+	return value != 0, nil
+}
+
+func (b boolean) SizeOf(interface{}) (int, error) {
+	return 1, nil
+}
+
+func (b boolean) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(bool)
+	if !ok {
+		return fmt.Errorf("%v is not a BOOLEAN", o)
+	}
+	if v {
+		return buffer.PutByte(1)
+	}
+	return buffer.PutByte(0)
+}
+
+func (b boolean) TypeName() string {
+	return "BOOLEAN"
+}
+
+func (b boolean) Validate(o interface{}) (interface{}, error) {
+	if s, ok := o.(bool); ok {
+		return s, nil
+	} else {
+		return nil, fmt.Errorf("%v is not a BOOLEAN", o)
+	}
+}
+
+func (b boolean) isNullable() bool {
+	return false
+}
+
+func (b boolean) String() string {
+	return strconv.FormatBool(bool(b))
+}
+
+type i8 int8
+
+func (i i8) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	return buffer.GetInt8()
+}
+
+func (i i8) SizeOf(interface{}) (int, error) {
+	return 1, nil
+}
+
+func (i i8) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(int8)
+	if !ok {
+		return fmt.Errorf("%v is not a INT8", o)
+	}
+	return buffer.PutInt8(v)
+}
+
+func (i i8) TypeName() string {
+	return "INT8"
+}
+
+func (i i8) Validate(o interface{}) (interface{}, error) {
+	if s, ok := o.(int8); ok {
+		return s, nil
+	} else {
+		return nil, fmt.Errorf("%v is not a INT8", o)
+	}
+}
+
+func (i i8) isNullable() bool {
+	return false
+}
+
+func (i i8) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+type u16 uint16
+
+func (i u16) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	v, err := buffer.GetInt16()
+	if err != nil {
+		return nil, err
+	}
+	return uint16(v), nil
+}
+
+func (i u16) SizeOf(interface{}) (int, error) {
+	return 2, nil
+}
+
+func (i u16) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(uint16)
+	if !ok {
+		return fmt.Errorf("%v is not a UINT16", o)
+	}
+	return buffer.PutInt16(int16(v))
+}
+
+func (i u16) TypeName() string {
+	return "UINT16"
+}
+
+func (i u16) Validate(o interface{}) (interface{}, error) {
+	if s, ok := o.(uint16); ok {
+		return s, nil
+	} else {
+		return nil, fmt.Errorf("%v is not a UINT16", o)
+	}
+}
+
+func (i u16) isNullable() bool {
+	return false
+}
+
+func (i u16) String() string {
+	return strconv.FormatUint(uint64(i), 10)
+}
+
+type u32 uint32
+
+func (i u32) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	v, err := buffer.GetInt32()
+	if err != nil {
+		return nil, err
+	}
+	return uint32(v), nil
+}
+
+func (i u32) SizeOf(interface{}) (int, error) {
+	return 4, nil
+}
+
+func (i u32) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(uint32)
+	if !ok {
+		return fmt.Errorf("%v is not a UINT32", o)
+	}
+	return buffer.PutInt32(int32(v))
+}
+
+func (i u32) TypeName() string {
+	return "UINT32"
+}
+
+func (i u32) Validate(o interface{}) (interface{}, error) {
+	if s, ok := o.(uint32); ok {
+		return s, nil
+	} else {
+		return nil, fmt.Errorf("%v is not a UINT32", o)
+	}
+}
+
+func (i u32) isNullable() bool {
+	return false
+}
+
+func (i u32) String() string {
+	return strconv.FormatUint(uint64(i), 10)
+}
+
+type f64 float64
+
+func (f f64) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	return buffer.GetFloat64()
+}
+
+func (f f64) SizeOf(interface{}) (int, error) {
+	return 8, nil
+}
+
+func (f f64) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(float64)
+	if !ok {
+		return fmt.Errorf("%v is not a FLOAT64", o)
+	}
+	return buffer.PutFloat64(v)
+}
+
+func (f f64) TypeName() string {
+	return "FLOAT64"
+}
+
+func (f f64) Validate(o interface{}) (interface{}, error) {
+	if s, ok := o.(float64); ok {
+		return s, nil
+	} else {
+		return nil, fmt.Errorf("%v is not a FLOAT64", o)
+	}
+}
+
+func (f f64) isNullable() bool {
+	return false
+}
+
+func (f f64) String() string {
+	return strconv.FormatFloat(float64(f), 'g', -1, 64)
+}
+
+type uuid struct{}
+
+func (u uuid) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	var value Uuid
+	for i := range value {
+		bt, err := buffer.Get()
+		if err != nil {
+			return nil, err
+		}
+		value[i] = bt
+	}
+	return value, nil
+}
+
+func (u uuid) SizeOf(interface{}) (int, error) {
+	return 16, nil
+}
+
+func (u uuid) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(Uuid)
+	if !ok {
+		return fmt.Errorf("%v is not a UUID", o)
+	}
+	return buffer.PutBytes(v[:])
+}
+
+func (u uuid) TypeName() string {
+	return "UUID"
+}
+
+func (u uuid) Validate(o interface{}) (interface{}, error) {
+	if s, ok := o.(Uuid); ok {
+		return s, nil
+	} else {
+		return nil, fmt.Errorf("%v is not a UUID", o)
+	}
+}
+
+func (u uuid) isNullable() bool {
+	return false
+}
+
+func (u uuid) String() string {
+	return ""
+}
+
 type i16 int
 
-func (i i16) Read(buffer *buffer.ByteBuffer) (interface{}, error) {
+func (i i16) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
 	return buffer.GetInt16()
 }
 
@@ -31,12 +297,20 @@ func (i i16) SizeOf(interface{}) (int, error) {
 	return 2, nil
 }
 
+func (i i16) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(int16)
+	if !ok {
+		return fmt.Errorf("%v is not a INT16", o)
+	}
+	return buffer.PutInt16(v)
+}
+
 func (i i16) TypeName() string {
 	return "INT16"
 }
 
 func (i i16) Validate(o interface{}) (interface{}, error) {
-	if s, ok := o.(i16); ok {
+	if s, ok := o.(int16); ok {
 		return s, nil
 	} else {
 		return nil, fmt.Errorf("%v is not a INT16", o)
@@ -53,7 +327,7 @@ func (i i16) String() string {
 
 type i32 int
 
-func (i i32) Read(buffer *buffer.ByteBuffer) (interface{}, error) {
+func (i i32) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
 	return buffer.GetInt32()
 }
 
@@ -61,6 +335,14 @@ func (i i32) SizeOf(interface{}) (int, error) {
 	return 4, nil
 }
 
+func (i i32) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(int32)
+	if !ok {
+		return fmt.Errorf("%v is not a INT32", o)
+	}
+	return buffer.PutInt32(v)
+}
+
 func (i i32) TypeName() string {
 	return "INT32"
 }
@@ -69,7 +351,7 @@ func (i i32) Validate(o interface{}) (interface{}, error) {
 	if s, ok := o.(int32); ok {
 		return s, nil
 	} else {
-		return nil, fmt.Errorf("%v is not a int", o)
+		return nil, fmt.Errorf("%v is not a INT32", o)
 	}
 }
 
@@ -83,7 +365,7 @@ func (i i32) String() string {
 
 type i64 int64
 
-func (i i64) Read(buffer *buffer.ByteBuffer) (interface{}, error) {
+func (i i64) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
 	return buffer.GetInt64()
 }
 
@@ -91,12 +373,20 @@ func (i i64) SizeOf(interface{}) (int, error) {
 	return 8, nil
 }
 
+func (i i64) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(int64)
+	if !ok {
+		return fmt.Errorf("%v is not a INT64", o)
+	}
+	return buffer.PutInt64(v)
+}
+
 func (i i64) TypeName() string {
 	return "INT64"
 }
 
 func (i i64) Validate(o interface{}) (interface{}, error) {
-	if s, ok := o.(i64); ok {
+	if s, ok := o.(int64); ok {
 		return s, nil
 	} else {
 		return nil, fmt.Errorf("%v is not a INT64", o)
@@ -111,18 +401,147 @@ func (i i64) String() string {
 	return strconv.FormatInt(int64(i), 10)
 }
 
+type varint int32
+
+func (i varint) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	return buffer.GetVarint()
+}
+
+func (i varint) SizeOf(o interface{}) (int, error) {
+	v, ok := o.(int32)
+	if !ok {
+		return 0, fmt.Errorf("%v is not a VARINT", o)
+	}
+	return buffer.SizeOfVarint(v), nil
+}
+
+func (i varint) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(int32)
+	if !ok {
+		return fmt.Errorf("%v is not a VARINT", o)
+	}
+	return buffer.PutVarint(v)
+}
+
+func (i varint) TypeName() string {
+	return "VARINT"
+}
+
+func (i varint) Validate(o interface{}) (interface{}, error) {
+	if s, ok := o.(int32); ok {
+		return s, nil
+	} else {
+		return nil, fmt.Errorf("%v is not a VARINT", o)
+	}
+}
+
+func (i varint) isNullable() bool {
+	return false
+}
+
+func (i varint) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+type varlong int64
+
+func (i varlong) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	return buffer.GetVarlong()
+}
+
+func (i varlong) SizeOf(o interface{}) (int, error) {
+	v, ok := o.(int64)
+	if !ok {
+		return 0, fmt.Errorf("%v is not a VARLONG", o)
+	}
+	return buffer.SizeOfVarlong(v), nil
+}
+
+func (i varlong) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(int64)
+	if !ok {
+		return fmt.Errorf("%v is not a VARLONG", o)
+	}
+	return buffer.PutVarlong(v)
+}
+
+func (i varlong) TypeName() string {
+	return "VARLONG"
+}
+
+func (i varlong) Validate(o interface{}) (interface{}, error) {
+	if s, ok := o.(int64); ok {
+		return s, nil
+	} else {
+		return nil, fmt.Errorf("%v is not a VARLONG", o)
+	}
+}
+
+func (i varlong) isNullable() bool {
+	return false
+}
+
+func (i varlong) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+type unsignedVarint int32
+
+func (i unsignedVarint) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	return buffer.GetUnsignedVarint()
+}
+
+func (i unsignedVarint) SizeOf(o interface{}) (int, error) {
+	v, ok := o.(int32)
+	if !ok {
+		return 0, fmt.Errorf("%v is not a UNSIGNED_VARINT", o)
+	}
+	return buffer.SizeOfUnsignedVarint(v), nil
+}
+
+func (i unsignedVarint) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	v, ok := o.(int32)
+	if !ok {
+		return fmt.Errorf("%v is not a UNSIGNED_VARINT", o)
+	}
+	return buffer.PutUnsignedVarint(v)
+}
+
+func (i unsignedVarint) TypeName() string {
+	return "UNSIGNED_VARINT"
+}
+
+func (i unsignedVarint) Validate(o interface{}) (interface{}, error) {
+	if s, ok := o.(int32); ok {
+		return s, nil
+	} else {
+		return nil, fmt.Errorf("%v is not a UNSIGNED_VARINT", o)
+	}
+}
+
+func (i unsignedVarint) isNullable() bool {
+	return false
+}
+
+func (i unsignedVarint) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
 type bytes struct{}
 
-func (b bytes) Read(buffer *buffer.ByteBuffer) (interface{}, error) {
+func (b bytes) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
 	size, err := buffer.GetInt32()
 	if err != nil {
-		return nil, fmt.Errorf("bytes length read faild: %v", err)
+		return nil, fmt.Errorf("bytes length read faild: %w", err)
 	}
 	if size < 0 {
-		return nil, fmt.Errorf("bytes size %v cannot be negative", size)
+		return nil, fmt.Errorf("%w: bytes size %v", ErrNegativeLength, size)
+	}
+	if err := opts.checkBytesLength(int(size)); err != nil {
+		return nil, err
 	}
 	if size > int32(buffer.Remaining()) {
-		return nil, fmt.Errorf("error reading bytes of size %d, only %d bytes available", size, buffer.Remaining())
+		return nil, fmt.Errorf("%w: reading bytes of size %d, only %d bytes available", ErrBufferUnderflow, size, buffer.Remaining())
 	}
 	slice := buffer.Slice()
 	_ = slice.SetLimit(int(size))
@@ -136,10 +555,21 @@ func (b bytes) SizeOf(o interface{}) (int, error) {
 		byteBuffer := o.(*buffer.ByteBuffer)
 		return 4 + byteBuffer.Remaining(), nil
 	default:
-		return 0, fmt.Errorf("the type is not a ByteBuffer")
+		return 0, fmt.Errorf("%v is not a BYTES", o)
 	}
 }
 
+func (b bytes) Write(buf *buffer.ByteBuffer, o interface{}) error {
+	byteBuffer, ok := o.(*buffer.ByteBuffer)
+	if !ok {
+		return fmt.Errorf("%v is not a BYTES", o)
+	}
+	if err := buf.PutInt32(int32(byteBuffer.Remaining())); err != nil {
+		return err
+	}
+	return buf.Put(byteBuffer.Duplicate())
+}
+
 func (b bytes) String() string {
 	return ""
 }
@@ -154,7 +584,7 @@ func (b bytes) Validate(o interface{}) (interface{}, error) {
 		byteBuffer := o.(*buffer.ByteBuffer)
 		return byteBuffer, nil
 	default:
-		return nil, fmt.Errorf("the type is not a ByteBuffer")
+		return nil, fmt.Errorf("%v is not a BYTES", o)
 	}
 }
 
@@ -164,16 +594,19 @@ func (b bytes) TypeName() string {
 
 type nullableBytes struct{}
 
-func (b nullableBytes) Read(buffer *buffer.ByteBuffer) (interface{}, error) {
+func (b nullableBytes) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
 	size, err := buffer.GetInt32()
 	if err != nil {
-		return nil, fmt.Errorf("bytes length read faild: %v", err)
+		return nil, fmt.Errorf("bytes length read faild: %w", err)
 	}
 	if size < 0 {
 		return nil, nil
 	}
+	if err := opts.checkBytesLength(int(size)); err != nil {
+		return nil, err
+	}
 	if size > int32(buffer.Remaining()) {
-		return nil, fmt.Errorf("error reading bytes of size %d, only %d bytes available", size, buffer.Remaining())
+		return nil, fmt.Errorf("%w: reading bytes of size %d, only %d bytes available", ErrBufferUnderflow, size, buffer.Remaining())
 	}
 	slice := buffer.Slice()
 	_ = slice.SetLimit(int(size))
@@ -190,10 +623,20 @@ func (b nullableBytes) SizeOf(o interface{}) (int, error) {
 		byteBuffer := o.(*buffer.ByteBuffer)
 		return 4 + byteBuffer.Remaining(), nil
 	default:
-		return 0, fmt.Errorf("the type is not a ByteBuffer")
+		return 0, fmt.Errorf("%v is not a NULLABLE_BYTES", o)
 	}
 }
 
+func (b nullableBytes) Write(buf *buffer.ByteBuffer, o interface{}) error {
+	if o == nil {
+		return buf.PutInt32(-1)
+	}
+	if _, ok := o.(*buffer.ByteBuffer); !ok {
+		return fmt.Errorf("%v is not a NULLABLE_BYTES", o)
+	}
+	return BYTES.Write(buf, o)
+}
+
 func (b nullableBytes) String() string {
 	return ""
 }
@@ -212,7 +655,7 @@ func (b nullableBytes) Validate(o interface{}) (interface{}, error) {
 		byteBuffer := o.(*buffer.ByteBuffer)
 		return byteBuffer, nil
 	default:
-		return nil, fmt.Errorf("the type is not a ByteBuffer")
+		return nil, fmt.Errorf("%v is not a NULLABLE_BYTES", o)
 	}
 }
 
@@ -222,25 +665,57 @@ func (b nullableBytes) TypeName() string {
 
 type s string
 
-func (s s) Read(buffer *buffer.ByteBuffer) (interface{}, error) {
+func (s s) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
 	// length is the bytes length of the string
 	length, err := buffer.GetInt16()
 	if err != nil {
-		return nil, fmt.Errorf("string length read faild: %v", err)
+		return nil, fmt.Errorf("string length read faild: %w", err)
 	}
 	if length < 0 {
-		return nil, fmt.Errorf("string length %d cannot be negative", length)
+		return nil, fmt.Errorf("%w: string length %d", ErrNegativeLength, length)
+	}
+	return readString(buffer, int(length), opts)
+}
+
+func readString(buffer *buffer.ByteBuffer, length int, opts *DecodeOptions) (interface{}, error) {
+	opts = opts.Begin()
+	if err := opts.checkBytesLength(length); err != nil {
+		return nil, err
 	}
-	if length > int16(buffer.Remaining()) {
-		return nil, fmt.Errorf("error reading string of length %d, only %d bytes available", length, buffer.Remaining())
+	if length > buffer.Remaining() {
+		return nil, fmt.Errorf("%w: reading string of length %d, only %d bytes available", ErrBufferUnderflow, length, buffer.Remaining())
 	}
-	str, err := buffer.GetString(0, int(length))
-	err = buffer.SetPosition(buffer.GetPosition() + int(length))
+	if err := opts.allocate(length); err != nil {
+		return nil, err
+	}
+	str, err := buffer.GetString(0, length)
+	if err != nil {
+		return nil, err
+	}
+	err = buffer.SetPosition(buffer.GetPosition() + length)
 	return str, err
 }
 
 func (s s) SizeOf(o interface{}) (int, error) {
-	return 2 + len(o.(string)), nil
+	str, ok := o.(string)
+	if !ok {
+		return 0, fmt.Errorf("%v is not a STRING", o)
+	}
+	return 2 + len(str), nil
+}
+
+func (s s) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	str, ok := o.(string)
+	if !ok {
+		return fmt.Errorf("%v is not a STRING", o)
+	}
+	if len(str) > math.MaxInt16 {
+		return fmt.Errorf("string length %d is larger than the maximum string length", len(str))
+	}
+	if err := buffer.PutInt16(int16(len(str))); err != nil {
+		return err
+	}
+	return buffer.PutBytes([]byte(str))
 }
 
 func (s s) String() string {
@@ -255,7 +730,7 @@ func (s s) Validate(o interface{}) (interface{}, error) {
 	if s, ok := o.(string); ok {
 		return s, nil
 	} else {
-		return nil, fmt.Errorf("%v is not a s", o)
+		return nil, fmt.Errorf("%v is not a STRING", o)
 	}
 }
 
@@ -265,21 +740,16 @@ func (s s) TypeName() string {
 
 type nullableString string
 
-func (s nullableString) Read(buffer *buffer.ByteBuffer) (interface{}, error) {
+func (s nullableString) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
 	// length is the bytes length of the string
 	length, err := buffer.GetInt16()
 	if err != nil {
-		return nil, fmt.Errorf("string length read faild: %v", err)
+		return nil, fmt.Errorf("string length read faild: %w", err)
 	}
 	if length < 0 {
-		return "", nil
-	}
-	if length > int16(buffer.Remaining()) {
-		return nil, fmt.Errorf("error reading string of length %d, only %d bytes available", length, buffer.Remaining())
+		return nil, nil
 	}
-	str, err := buffer.GetString(0, int(length))
-	err = buffer.SetPosition(buffer.GetPosition() + int(length))
-	return str, err
+	return readString(buffer, int(length), opts)
 }
 
 func (s nullableString) SizeOf(o interface{}) (int, error) {
@@ -287,7 +757,20 @@ func (s nullableString) SizeOf(o interface{}) (int, error) {
 		return 2, nil
 	}
 
-	return 2 + len(o.(string)), nil
+	if _, ok := o.(string); !ok {
+		return 0, fmt.Errorf("%v is not a NULLABLE_STRING", o)
+	}
+	return STRING.SizeOf(o)
+}
+
+func (s nullableString) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	if o == nil {
+		return buffer.PutInt16(-1)
+	}
+	if _, ok := o.(string); !ok {
+		return fmt.Errorf("%v is not a NULLABLE_STRING", o)
+	}
+	return STRING.Write(buffer, o)
 }
 
 func (s nullableString) String() string {
@@ -306,7 +789,7 @@ func (s nullableString) Validate(o interface{}) (interface{}, error) {
 	if s, ok := o.(string); ok {
 		return s, nil
 	} else {
-		return nil, fmt.Errorf("%v is not a s", o)
+		return nil, fmt.Errorf("%v is not a NULLABLE_STRING", o)
 	}
 }
 
@@ -314,79 +797,449 @@ func (s nullableString) TypeName() string {
 	return "NULLABLE_STRING"
 }
 
-type arrayOf struct {
+type compactString string
+
+func (s compactString) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	length, err := buffer.GetUnsignedVarint()
+	if err != nil {
+		return nil, fmt.Errorf("compact string length read faild: %w", err)
+	}
+	length--
+	if length < 0 {
+		return nil, fmt.Errorf("%w: compact string length %d", ErrNegativeLength, length)
+	}
+	return readCompactString(buffer, length, opts)
+}
+
+func readCompactString(buffer *buffer.ByteBuffer, length int32, opts *DecodeOptions) (interface{}, error) {
+	if length > math.MaxInt16 {
+		return nil, fmt.Errorf("string length %d is larger than the maximum string length", length)
+	}
+	return readString(buffer, int(length), opts)
+}
+
+func (s compactString) SizeOf(o interface{}) (int, error) {
+	str, ok := o.(string)
+	if !ok {
+		return 0, fmt.Errorf("%v is not a COMPACT_STRING", o)
+	}
+	return buffer.SizeOfUnsignedVarint(int32(len(str)+1)) + len(str), nil
+}
+
+func (s compactString) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	str, ok := o.(string)
+	if !ok {
+		return fmt.Errorf("%v is not a COMPACT_STRING", o)
+	}
+	if len(str) > math.MaxInt16 {
+		return fmt.Errorf("string length %d is larger than the maximum string length", len(str))
+	}
+	if err := buffer.PutUnsignedVarint(int32(len(str) + 1)); err != nil {
+		return err
+	}
+	return buffer.PutString(str)
+}
+
+func (s compactString) String() string {
+	return string(s)
+}
+
+func (s compactString) isNullable() bool {
+	return false
+}
+
+func (s compactString) Validate(o interface{}) (interface{}, error) {
+	return STRING.Validate(o)
+}
+
+func (s compactString) TypeName() string {
+	return "COMPACT_STRING"
+}
+
+type compactNullableString string
+
+func (s compactNullableString) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	length, err := buffer.GetUnsignedVarint()
+	if err != nil {
+		return nil, fmt.Errorf("compact string length read faild: %w", err)
+	}
+	length--
+	if length < 0 {
+		return nil, nil
+	}
+	return readCompactString(buffer, length, opts)
+}
+
+func (s compactNullableString) SizeOf(o interface{}) (int, error) {
+	if o == nil {
+		return 1, nil
+	}
+	if _, ok := o.(string); !ok {
+		return 0, fmt.Errorf("%v is not a COMPACT_NULLABLE_STRING", o)
+	}
+	return CompactString.SizeOf(o)
+}
+
+func (s compactNullableString) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	if o == nil {
+		return buffer.PutUnsignedVarint(0)
+	}
+	if _, ok := o.(string); !ok {
+		return fmt.Errorf("%v is not a COMPACT_NULLABLE_STRING", o)
+	}
+	return CompactString.Write(buffer, o)
+}
+
+func (s compactNullableString) String() string {
+	return string(s)
+}
+
+func (s compactNullableString) isNullable() bool {
+	return true
+}
+
+func (s compactNullableString) Validate(o interface{}) (interface{}, error) {
+	return NullableString.Validate(o)
+}
+
+func (s compactNullableString) TypeName() string {
+	return "COMPACT_NULLABLE_STRING"
+}
+
+type compactBytes struct{}
+
+func (b compactBytes) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	size, err := buffer.GetUnsignedVarint()
+	if err != nil {
+		return nil, fmt.Errorf("compact bytes length read faild: %w", err)
+	}
+	size--
+	if size < 0 {
+		return nil, fmt.Errorf("%w: compact bytes size %v", ErrNegativeLength, size)
+	}
+	return readCompactBytes(buffer, size, opts)
+}
+
+func readCompactBytes(buffer *buffer.ByteBuffer, size int32, opts *DecodeOptions) (interface{}, error) {
+	if err := opts.checkBytesLength(int(size)); err != nil {
+		return nil, err
+	}
+	if int(size) > buffer.Remaining() {
+		return nil, fmt.Errorf("%w: reading bytes of size %d, only %d bytes available", ErrBufferUnderflow, size, buffer.Remaining())
+	}
+	slice := buffer.Slice()
+	_ = slice.SetLimit(int(size))
+	_ = buffer.SetPosition(buffer.GetPosition() + int(size))
+	return slice, nil
+}
+
+func (b compactBytes) SizeOf(o interface{}) (int, error) {
+	byteBuffer, ok := o.(*buffer.ByteBuffer)
+	if !ok {
+		return 0, fmt.Errorf("%v is not a COMPACT_BYTES", o)
+	}
+	return buffer.SizeOfUnsignedVarint(int32(byteBuffer.Remaining()+1)) + byteBuffer.Remaining(), nil
+}
+
+func (b compactBytes) Write(buf *buffer.ByteBuffer, o interface{}) error {
+	byteBuffer, ok := o.(*buffer.ByteBuffer)
+	if !ok {
+		return fmt.Errorf("%v is not a COMPACT_BYTES", o)
+	}
+	if err := buf.PutUnsignedVarint(int32(byteBuffer.Remaining() + 1)); err != nil {
+		return err
+	}
+	return buf.Put(byteBuffer.Duplicate())
+}
+
+func (b compactBytes) String() string {
+	return ""
+}
+
+func (b compactBytes) isNullable() bool {
+	return false
+}
+
+func (b compactBytes) Validate(o interface{}) (interface{}, error) {
+	return BYTES.Validate(o)
+}
+
+func (b compactBytes) TypeName() string {
+	return "COMPACT_BYTES"
+}
+
+type compactNullableBytes struct{}
+
+func (b compactNullableBytes) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	size, err := buffer.GetUnsignedVarint()
+	if err != nil {
+		return nil, fmt.Errorf("compact bytes length read faild: %w", err)
+	}
+	size--
+	if size < 0 {
+		return nil, nil
+	}
+	return readCompactBytes(buffer, size, opts)
+}
+
+func (b compactNullableBytes) SizeOf(o interface{}) (int, error) {
+	if o == nil {
+		return 1, nil
+	}
+	if _, ok := o.(*buffer.ByteBuffer); !ok {
+		return 0, fmt.Errorf("%v is not a COMPACT_NULLABLE_BYTES", o)
+	}
+	return CompactBytes.SizeOf(o)
+}
+
+func (b compactNullableBytes) Write(buf *buffer.ByteBuffer, o interface{}) error {
+	if o == nil {
+		return buf.PutUnsignedVarint(0)
+	}
+	if _, ok := o.(*buffer.ByteBuffer); !ok {
+		return fmt.Errorf("%v is not a COMPACT_NULLABLE_BYTES", o)
+	}
+	return CompactBytes.Write(buf, o)
+}
+
+func (b compactNullableBytes) String() string {
+	return ""
+}
+
+func (b compactNullableBytes) isNullable() bool {
+	return true
+}
+
+func (b compactNullableBytes) Validate(o interface{}) (interface{}, error) {
+	return NullableBytes.Validate(o)
+}
+
+func (b compactNullableBytes) TypeName() string {
+	return "COMPACT_NULLABLE_BYTES"
+}
+
+type ArrayOf struct {
 	t        Type
 	nullable bool
 }
 
-func NewArrayOf(t Type) *arrayOf {
+func NewArrayOf(t Type) *ArrayOf {
 	return NewArrayOf1(t, false)
 }
 
-func NewArrayOf1(t Type, nullable bool) *arrayOf {
-	return &arrayOf{t: t, nullable: nullable}
+func NewArrayOf1(t Type, nullable bool) *ArrayOf {
+	return &ArrayOf{t: t, nullable: nullable}
 }
 
-func (a arrayOf) Read(buffer *buffer.ByteBuffer) (interface{}, error) {
+// ElementType returns the type of the elements of the array
+func (a ArrayOf) ElementType() Type {
+	return a.t
+}
+
+func (a ArrayOf) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	opts = opts.Begin()
 	size, err := buffer.GetInt32()
 	if err != nil {
-		return nil, fmt.Errorf("arrayOf read buffer failed: %s", err)
+		return nil, fmt.Errorf("array read buffer failed: %w", err)
 	}
 	if size < 0 && a.isNullable() {
 		return nil, nil
 	} else if size < 0 {
-		return nil, fmt.Errorf("array size %v cannot be negative", size)
+		return nil, fmt.Errorf("%w: array size %v", ErrNegativeLength, size)
 	}
 
+	if err := opts.checkArrayLength(int(size)); err != nil {
+		return nil, err
+	}
 	if int(size) > buffer.Remaining() {
-		return nil, fmt.Errorf(fmt.Sprintf("Error reading array of size '%d', only '%d' bytes available", size, buffer.Remaining()))
+		return nil, fmt.Errorf("%w: reading array of size %d, only %d bytes available", ErrBufferUnderflow, size, buffer.Remaining())
+	}
+	if err := opts.allocate(int(size) * interfaceSize); err != nil {
+		return nil, err
 	}
 	objs := make([]interface{}, size)
 	for i := range objs {
-		buff, err := a.t.Read(buffer)
+		offset := buffer.AbsolutePosition()
+		buff, err := a.t.Read(buffer, opts)
 		if err != nil {
-			return nil, err
+			return nil, LocateDecodeError(err, fmt.Sprintf("[%d]", i), offset)
 		}
 		objs[i] = buff
 	}
 	return objs, nil
 }
 
-func (a arrayOf) SizeOf(o interface{}) (int, error) {
+func (a ArrayOf) SizeOf(o interface{}) (int, error) {
 	size := 4
 	if o == nil {
 		return size, nil
 	}
 
-	array := o.([]interface{})
+	array, ok := o.([]interface{})
+	if !ok {
+		return 0, fmt.Errorf("%v is not an array", o)
+	}
 	for _, obj := range array {
-		objSize, _ := a.t.SizeOf(obj)
+		objSize, err := a.t.SizeOf(obj)
+		if err != nil {
+			return 0, err
+		}
 		size = size + objSize
 	}
 	return size, nil
 }
 
-func (a arrayOf) String() string {
+func (a ArrayOf) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	if o == nil && a.isNullable() {
+		return buffer.PutInt32(-1)
+	}
+	array, ok := o.([]interface{})
+	if !ok {
+		return fmt.Errorf("%v is not an array", o)
+	}
+	if err := buffer.PutInt32(int32(len(array))); err != nil {
+		return err
+	}
+	for _, obj := range array {
+		if err := a.t.Write(buffer, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a ArrayOf) String() string {
 	return fmt.Sprintf("ARRAY(%s)", a.t.String())
 }
 
-func (a arrayOf) isNullable() bool {
+func (a ArrayOf) isNullable() bool {
 	return a.nullable
 }
 
-func (a arrayOf) Validate(o interface{}) (interface{}, error) {
+func (a ArrayOf) Validate(o interface{}) (interface{}, error) {
 	if a.isNullable() && o == nil {
 		return nil, nil
 	}
 
-	array := o.([]interface{})
-	var err error
+	array, ok := o.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%v is not an array", o)
+	}
 	for _, obj := range array {
-		_, err = a.t.Validate(obj)
+		if _, err := a.t.Validate(obj); err != nil {
+			return nil, err
+		}
 	}
-	return array, err
+	return array, nil
 }
 
-func (a arrayOf) TypeName() string {
+func (a ArrayOf) TypeName() string {
 	return "ARRAY"
 }
+
+type CompactArrayOf struct {
+	t        Type
+	nullable bool
+}
+
+func NewCompactArrayOf(t Type) *CompactArrayOf {
+	return NewCompactArrayOf1(t, false)
+}
+
+func NewCompactArrayOf1(t Type, nullable bool) *CompactArrayOf {
+	return &CompactArrayOf{t: t, nullable: nullable}
+}
+
+// ElementType returns the type of the elements of the array
+func (a CompactArrayOf) ElementType() Type {
+	return a.t
+}
+
+func (a CompactArrayOf) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	opts = opts.Begin()
+	n, err := buffer.GetUnsignedVarint()
+	if err != nil {
+		return nil, fmt.Errorf("compact array read buffer failed: %w", err)
+	}
+	size := n - 1
+	if size < 0 && a.isNullable() {
+		return nil, nil
+	} else if size < 0 {
+		return nil, fmt.Errorf("%w: array size %v", ErrNegativeLength, size)
+	}
+
+	if err := opts.checkArrayLength(int(size)); err != nil {
+		return nil, err
+	}
+	if int(size) > buffer.Remaining() {
+		return nil, fmt.Errorf("%w: reading array of size %d, only %d bytes available", ErrBufferUnderflow, size, buffer.Remaining())
+	}
+	if err := opts.allocate(int(size) * interfaceSize); err != nil {
+		return nil, err
+	}
+	objs := make([]interface{}, size)
+	for i := range objs {
+		offset := buffer.AbsolutePosition()
+		buff, err := a.t.Read(buffer, opts)
+		if err != nil {
+			return nil, LocateDecodeError(err, fmt.Sprintf("[%d]", i), offset)
+		}
+		objs[i] = buff
+	}
+	return objs, nil
+}
+
+func (a CompactArrayOf) SizeOf(o interface{}) (int, error) {
+	if o == nil {
+		return 1, nil
+	}
+
+	array, ok := o.([]interface{})
+	if !ok {
+		return 0, fmt.Errorf("%v is not an array", o)
+	}
+	size := buffer.SizeOfUnsignedVarint(int32(len(array) + 1))
+	for _, obj := range array {
+		objSize, err := a.t.SizeOf(obj)
+		if err != nil {
+			return 0, err
+		}
+		size = size + objSize
+	}
+	return size, nil
+}
+
+func (a CompactArrayOf) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	if o == nil && a.isNullable() {
+		return buffer.PutUnsignedVarint(0)
+	}
+	array, ok := o.([]interface{})
+	if !ok {
+		return fmt.Errorf("%v is not an array", o)
+	}
+	if err := buffer.PutUnsignedVarint(int32(len(array) + 1)); err != nil {
+		return err
+	}
+	for _, obj := range array {
+		if err := a.t.Write(buffer, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a CompactArrayOf) String() string {
+	return fmt.Sprintf("COMPACT_ARRAY(%s)", a.t.String())
+}
+
+func (a CompactArrayOf) isNullable() bool {
+	return a.nullable
+}
+
+func (a CompactArrayOf) Validate(o interface{}) (interface{}, error) {
+	return ArrayOf(a).Validate(o)
+}
+
+func (a CompactArrayOf) TypeName() string {
+	return "COMPACT_ARRAY"
+}
@@ -0,0 +1,96 @@
+package kafkaschema
+
+import (
+	"errors"
+	"fmt"
+	"kafka_schema/schema/buffer"
+	"strings"
+)
+
+var (
+	// ErrBufferUnderflow is returned when a value needs more bytes than remain in the buffer
+	ErrBufferUnderflow = buffer.ErrBufferUnderflow
+	// ErrNegativeLength is returned when the length of a value that cannot be null is negative
+	ErrNegativeLength = errors.New("negative length")
+	// ErrUnknownVersion is returned when there is no schema for the version of a message
+	ErrUnknownVersion = errors.New("unknown version")
+	// ErrTypeMismatch is returned when a value is not of the type expected for it
+	ErrTypeMismatch = errors.New("type mismatch")
+	// ErrTrailingBytes is reported when bytes remain after a message was read
+	ErrTrailingBytes = errors.New("trailing bytes")
+	// ErrLimitExceeded is returned when a decoding exceeds a limit of its DecodeOptions
+	ErrLimitExceeded = errors.New("decode limit exceeded")
+)
+
+// DecodeError is an error of reading a value which locates the failure. Path is the path
+// of the field that failed, e.g. "members[1].subscription.topics[3]", and Offset is the
+// offset of its first byte in the original buffer. Schema and Version name the message
+// being decoded when known, Version is -1 for a message without version. Offset is -1 until
+// the message is named when the failing field has no offset of its own, e.g. a value read
+// before which cannot be converted.
+type DecodeError struct {
+	Schema  string
+	Version int
+	Path    string
+	Offset  int
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("error reading ")
+	if e.Schema != "" {
+		sb.WriteString(e.Schema)
+		if e.Version >= 0 {
+			fmt.Fprintf(&sb, " V%d", e.Version)
+		}
+		sb.WriteString(" ")
+	}
+	if e.Path != "" {
+		fmt.Fprintf(&sb, "field '%s' ", e.Path)
+	}
+	fmt.Fprintf(&sb, "at offset %d: %v", e.Offset, e.Err)
+	return sb.String()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// LocateDecodeError returns err located under the path, the path is prepended to the path
+// of the DecodeError err wraps and any other error becomes a DecodeError at the path and
+// offset, -1 when the field has no offset. The DecodeError err wraps is left untouched as
+// it may be shared, the returned one wraps its cause.
+func LocateDecodeError(err error, path string, offset int) error {
+	var de *DecodeError
+	if errors.As(err, &de) {
+		return &DecodeError{Schema: de.Schema, Version: de.Version, Path: joinDecodePath(path, de.Path), Offset: de.Offset, Err: de.Err}
+	}
+	return &DecodeError{Version: -1, Path: path, Offset: offset, Err: err}
+}
+
+// NameDecodeError returns err for the message being decoded, the outermost message names
+// the error as the path starts from it. A DecodeError without offset is at the offset of
+// the message and any other error becomes a DecodeError at the offset. Like
+// LocateDecodeError it returns a new DecodeError wrapping the cause.
+func NameDecodeError(err error, schema string, version int, offset int) error {
+	var de *DecodeError
+	if errors.As(err, &de) {
+		if de.Offset >= 0 {
+			offset = de.Offset
+		}
+		return &DecodeError{Schema: schema, Version: version, Path: de.Path, Offset: offset, Err: de.Err}
+	}
+	return &DecodeError{Schema: schema, Version: version, Offset: offset, Err: err}
+}
+
+func joinDecodePath(path, name string) string {
+	if path == "" {
+		return name
+	} else if name == "" {
+		return path
+	} else if strings.HasPrefix(name, "[") {
+		return path + name
+	}
+	return path + "." + name
+}
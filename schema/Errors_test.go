@@ -0,0 +1,82 @@
+package kafkaschema
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestLocateAndNameDecodeError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  func() error
+		want DecodeError
+	}{
+		{
+			name: "plain error",
+			err:  func() error { return ErrBufferUnderflow },
+			want: DecodeError{Schema: "Value", Version: 2, Path: "members[1]", Offset: 7},
+		},
+		{
+			name: "decode error",
+			err: func() error {
+				return &DecodeError{Version: -1, Path: "topics[3]", Offset: 12, Err: ErrBufferUnderflow}
+			},
+			want: DecodeError{Schema: "Value", Version: 2, Path: "members[1].topics[3]", Offset: 12},
+		},
+		{
+			name: "wrapped decode error",
+			err: func() error {
+				return fmt.Errorf("subscription: %w", &DecodeError{Version: -1, Path: "topics[3]", Offset: 12, Err: ErrBufferUnderflow})
+			},
+			want: DecodeError{Schema: "Value", Version: 2, Path: "members[1].topics[3]", Offset: 12},
+		},
+		{
+			name: "decode error without offset",
+			err:  func() error { return LocateDecodeError(ErrBufferUnderflow, "topics", -1) },
+			want: DecodeError{Schema: "Value", Version: 2, Path: "members[1].topics", Offset: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := LocateDecodeError(LocateDecodeError(tt.err(), "[1]", 7), "members", 3)
+			err = NameDecodeError(err, "Value", 2, 0)
+			if !errors.Is(err, ErrBufferUnderflow) {
+				t.Errorf("errors.Is(%v, ErrBufferUnderflow) = false", err)
+			}
+			var de *DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("errors.As(%v) = false", err)
+			}
+			if de.Schema != tt.want.Schema || de.Version != tt.want.Version || de.Path != tt.want.Path || de.Offset != tt.want.Offset {
+				t.Errorf("DecodeError = %+v, want %+v", *de, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocateDecodeErrorDoesNotMutate(t *testing.T) {
+	shared := &DecodeError{Version: -1, Path: "topics", Offset: 4, Err: ErrNegativeLength}
+	wrapped := fmt.Errorf("subscription: %w", shared)
+	first := NameDecodeError(LocateDecodeError(wrapped, "members[0]", 0), "Value", 3, 0)
+	second := LocateDecodeError(shared, "members[1]", 0)
+
+	if shared.Path != "topics" || shared.Schema != "" || shared.Version != -1 {
+		t.Errorf("shared DecodeError = %+v, want it unchanged", *shared)
+	}
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: first, want: "error reading Value V3 field 'members[0].topics' at offset 4: negative length"},
+		{err: second, want: "error reading field 'members[1].topics' at offset 4: negative length"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %s, want %s", got, tt.want)
+		}
+		if !errors.Is(tt.err, ErrNegativeLength) {
+			t.Errorf("errors.Is(%v, ErrNegativeLength) = false", tt.err)
+		}
+	}
+}
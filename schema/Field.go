@@ -12,21 +12,44 @@ func NewField(name string, t Type) *Field {
 	return newField(name, t, "", false, nil)
 }
 
+// NewField1 returns a field with a default value, the value of a Struct that was not set.
+// The default value is validated against the type when the field is added to a schema.
 func NewField1(name string, t Type, docString string, defaultValue interface{}) *Field {
-	return newField(name, t, docString, false, defaultValue)
+	return newField(name, t, docString, true, defaultValue)
+}
+
+// NewField2 returns a documented field without a default value
+func NewField2(name string, t Type, docString string) *Field {
+	return newField(name, t, docString, false, nil)
 }
 
 func newField(name string, t Type, docString string, hasDefaultValue bool, defaultValue interface{}) *Field {
-	field := &Field{
+	return &Field{
 		name:            name,
 		t:               t,
 		docString:       docString,
 		hasDefaultValue: hasDefaultValue,
 		defaultValue:    defaultValue,
 	}
-	//if hasDefaultValue {
-	//	_, err := field.t.Validate(defaultValue)
-	//	return nil, err
-	//}
-	return field
+}
+
+func (f *Field) Name() string {
+	return f.name
+}
+
+func (f *Field) Type() Type {
+	return f.t
+}
+
+func (f *Field) Doc() string {
+	return f.docString
+}
+
+// Default returns the default value of the field and false if the field has no default
+func (f *Field) Default() (interface{}, bool) {
+	return f.defaultValue, f.hasDefaultValue
+}
+
+func (f *Field) HasDefault() bool {
+	return f.hasDefaultValue
 }
@@ -3,11 +3,13 @@ package kafkaschema
 import (
 	"fmt"
 	"kafka_schema/schema/buffer"
+	"strings"
 )
 
 type Schema struct {
 	fields       []*BoundField
 	fieldsByName map[string]*BoundField
+	taggedFields *BoundField
 	Type
 }
 
@@ -17,6 +19,12 @@ func NewSchema(fs ...*Field) (*Schema, error) {
 	return schema, err
 }
 
+// NewFlexibleSchema returns a schema of a flexible version, the fields are followed
+// by a tagged fields section without any known tag
+func NewFlexibleSchema(fs ...*Field) (*Schema, error) {
+	return NewSchema(append(fs, NewTaggedFieldsSection(nil))...)
+}
+
 func (sch *Schema) init(fs ...*Field) error {
 	sch.fields = make([]*BoundField, len(fs))
 	sch.fieldsByName = make(map[string]*BoundField)
@@ -25,27 +33,86 @@ func (sch *Schema) init(fs ...*Field) error {
 		if _, ok := sch.fieldsByName[def.name]; ok {
 			return fmt.Errorf("schema contains a duplicate field: %s", def.name)
 		}
+		if _, ok := def.t.(*TaggedFields); ok && i != len(fs)-1 {
+			return fmt.Errorf("the tagged fields section must be the last field of the schema")
+		}
+		if def.hasDefaultValue {
+			if _, err := def.t.Validate(def.defaultValue); err != nil {
+				return fmt.Errorf("invalid default value for field '%s': %v", def.name, err)
+			}
+		}
 		sch.fields[i] = NewBoundField(def, sch, i)
 		sch.fieldsByName[def.name] = sch.fields[i]
 	}
+	if len(sch.fields) > 0 {
+		last := sch.fields[len(sch.fields)-1]
+		if _, ok := last.def.t.(*TaggedFields); ok {
+			sch.taggedFields = last
+		}
+	}
 	return nil
 }
 
-func (sch *Schema) Read(buffer *buffer.ByteBuffer) (interface{}, error) {
+// Fields returns the fields of the schema in order, the tagged fields section included
+func (sch *Schema) Fields() []*BoundField {
+	fields := make([]*BoundField, len(sch.fields))
+	copy(fields, sch.fields)
+	return fields
+}
+
+// IsFlexible returns true if the schema ends with a tagged fields section
+func (sch *Schema) IsFlexible() bool {
+	return sch.taggedFields != nil
+}
+
+func (sch *Schema) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	opts = opts.Begin()
+	if err := opts.enter(); err != nil {
+		return nil, err
+	}
+	defer opts.leave()
+	if err := opts.allocate(len(sch.fields) * interfaceSize); err != nil {
+		return nil, err
+	}
 	objects := make([]interface{}, len(sch.fields))
 	for i := 0; i < len(sch.fields); i++ {
-		obj, err := sch.fields[i].def.t.Read(buffer)
+		offset := buffer.AbsolutePosition()
+		obj, err := sch.fields[i].def.t.Read(buffer, opts)
 		if err != nil {
-			return nil, fmt.Errorf("error reading field '%s': %v", sch.fields[i].def.name, err)
+			return nil, LocateDecodeError(err, sch.fields[i].def.name, offset)
 		}
 		objects[i] = obj
 	}
 
-	return NewStruct(sch, objects), nil
+	return newReadStruct(sch, objects), nil
+}
+
+func (sch *Schema) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	r, ok := o.(*Struct)
+	if !ok {
+		return fmt.Errorf("%v is not a Struct", o)
+	}
+	for _, field := range sch.fields {
+		f, err := r.GetField(field)
+		if err != nil {
+			return fmt.Errorf("error writing field '%s': %v", field.def.name, err)
+		}
+		f, err = field.def.t.Validate(f)
+		if err != nil {
+			return fmt.Errorf("error writing field '%s': %v", field.def.name, err)
+		}
+		if err = field.def.t.Write(buffer, f); err != nil {
+			return fmt.Errorf("error writing field '%s': %v", field.def.name, err)
+		}
+	}
+	return nil
 }
 
 func (sch *Schema) SizeOf(o interface{}) (int, error) {
-	r := o.(Struct)
+	r, ok := o.(*Struct)
+	if !ok {
+		return 0, fmt.Errorf("%v is not a Struct", o)
+	}
 	var size int
 	for _, field := range sch.fields {
 		f, err := r.GetField(field)
@@ -61,18 +128,26 @@ func (sch *Schema) SizeOf(o interface{}) (int, error) {
 	return size, nil
 }
 
+// Validate checks that every field of the Struct has a valid value or a default, all the
+// missing and invalid fields are reported in the error
 func (sch *Schema) Validate(o interface{}) (interface{}, error) {
-	r := o.(*Struct)
+	r, ok := o.(*Struct)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a Struct", o)
+	}
+	var invalid []string
 	for _, field := range sch.fields {
 		f, err := r.GetField(field)
-		if err != nil {
-			return nil, fmt.Errorf("invalid value for field '%s': %v", field.def.name, err)
+		if err == nil {
+			_, err = field.def.t.Validate(f)
 		}
-		_, err = field.def.t.Validate(f)
 		if err != nil {
-			return nil, fmt.Errorf("invalid value for field '%s': %v", field.def.name, err)
+			invalid = append(invalid, fmt.Sprintf("invalid value for field '%s': %v", field.def.name, err))
 		}
 	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(invalid, "; "))
+	}
 	return r, nil
 }
 
@@ -80,8 +155,30 @@ func (sch *Schema) isNullable() bool {
 	return false
 }
 
+// String returns the fields of the schema with their types, e.g. "{version:INT16,topic:STRING}"
 func (sch *Schema) String() string {
-	return fmt.Sprintf("schema with fields by name: %v", sch.fieldsByName)
+	fields := make([]string, len(sch.fields))
+	for i, field := range sch.fields {
+		fields[i] = field.String()
+	}
+	return "{" + strings.Join(fields, ",") + "}"
+}
+
+// TypeName returns the protocol name of a type, e.g. "ARRAY(INT32)", the value based String of the
+// primitive types does not name them
+func TypeName(t Type) string {
+	switch tt := t.(type) {
+	case *Schema:
+		return tt.String()
+	case *ArrayOf:
+		return fmt.Sprintf("ARRAY(%s)", TypeName(tt.t))
+	case *CompactArrayOf:
+		return fmt.Sprintf("COMPACT_ARRAY(%s)", TypeName(tt.t))
+	case DocumentedType:
+		return tt.TypeName()
+	default:
+		return t.String()
+	}
 }
 
 func (sch *Schema) Get(name string) (*BoundField, error) {
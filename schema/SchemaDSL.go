@@ -0,0 +1,366 @@
+package kafkaschema
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The schema DSL is a list of "name:TYPE" fields separated by spaces or commas, e.g.
+//
+//	group:STRING topic:STRING partition:INT32 members:ARRAY(member_id:STRING, assignment:BYTES)
+//
+// TYPE is the name of a primitive type, a struct "{name:TYPE ...}", an array
+// ARRAY(TYPE) or ARRAY(name:TYPE ...) for an array of structs, with the variants
+// NULLABLE_ARRAY, COMPACT_ARRAY and COMPACT_NULLABLE_ARRAY, or a tagged fields section
+// TAGGED_FIELDS or TAGGED_FIELDS(tag=name:TYPE ...). The docs and defaults of the fields
+// are not part of the DSL.
+
+var dslPrimitiveTypes = map[string]Type{
+	"BOOLEAN":                 BOOLEAN,
+	"INT8":                    INT8,
+	"INT16":                   INT16,
+	"INT32":                   INT32,
+	"INT64":                   INT64,
+	"UINT16":                  UINT16,
+	"UINT32":                  UINT32,
+	"FLOAT64":                 FLOAT64,
+	"UUID":                    UUID,
+	"VARINT":                  VARINT,
+	"VARLONG":                 VARLONG,
+	"UNSIGNED_VARINT":         UnsignedVarint,
+	"STRING":                  STRING,
+	"NULLABLE_STRING":         NullableString,
+	"BYTES":                   BYTES,
+	"NULLABLE_BYTES":          NullableBytes,
+	"COMPACT_STRING":          CompactString,
+	"COMPACT_NULLABLE_STRING": CompactNullableString,
+	"COMPACT_BYTES":           CompactBytes,
+	"COMPACT_NULLABLE_BYTES":  CompactNullableBytes,
+}
+
+// ParseError is an error of ParseSchema at a byte offset of the text
+type ParseError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("schema parse error at offset %d: %s", e.Offset, e.Msg)
+}
+
+// ParseSchema parses a schema written in the schema DSL, the text can be enclosed in
+// braces so that the String of a schema without tagged fields is parsed as well
+func ParseSchema(text string) (*Schema, error) {
+	p := &dslParser{text: text}
+	p.skipSpaces()
+	end := byte(0)
+	if p.peek() == '{' {
+		p.pos++
+		end = '}'
+	}
+	schema, err := p.fields(end)
+	if err != nil {
+		return nil, err
+	}
+	if end != 0 {
+		p.pos++
+	}
+	p.skipSpaces()
+	if p.pos < len(p.text) {
+		return nil, p.errorf("unexpected '%c'", p.text[p.pos])
+	}
+	return schema, nil
+}
+
+type dslParser struct {
+	text string
+	pos  int
+}
+
+func (p *dslParser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *dslParser) peek() byte {
+	if p.pos < len(p.text) {
+		return p.text[p.pos]
+	}
+	return 0
+}
+
+func (p *dslParser) skipSpaces() {
+	for p.pos < len(p.text) && strings.IndexByte(" \t\r\n", p.text[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *dslParser) skipSeparators() {
+	for p.pos < len(p.text) && strings.IndexByte(" \t\r\n,", p.text[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *dslParser) expect(c byte) error {
+	p.skipSpaces()
+	if p.peek() != c {
+		if p.pos >= len(p.text) {
+			return p.errorf("expected '%c' but the text ended", c)
+		}
+		return p.errorf("expected '%c' but found '%c'", c, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+func (p *dslParser) identifier() string {
+	start := p.pos
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		if c == '_' || c == '-' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+			p.pos++
+		} else {
+			break
+		}
+	}
+	return p.text[start:p.pos]
+}
+
+// isField returns true if the next token is a field, an identifier followed by a colon
+func (p *dslParser) isField() bool {
+	start := p.pos
+	defer func() { p.pos = start }()
+	p.skipSpaces()
+	if p.identifier() == "" {
+		return false
+	}
+	p.skipSpaces()
+	return p.peek() == ':'
+}
+
+// fields parses fields up to the end character, 0 for the end of the text
+func (p *dslParser) fields(end byte) (*Schema, error) {
+	var fs []*Field
+	start := p.pos
+	for {
+		p.skipSeparators()
+		if p.pos >= len(p.text) {
+			if end != 0 {
+				return nil, p.errorf("expected '%c' but the text ended", end)
+			}
+			break
+		}
+		if p.peek() == end {
+			break
+		}
+		field, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, field)
+	}
+	schema, err := NewSchema(fs...)
+	if err != nil {
+		return nil, &ParseError{Offset: start, Msg: err.Error()}
+	}
+	return schema, nil
+}
+
+func (p *dslParser) field() (*Field, error) {
+	name := p.identifier()
+	if name == "" {
+		return nil, p.errorf("expected a field name but found '%c'", p.peek())
+	}
+	if err := p.expect(':'); err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	t, err := p.fieldType()
+	if err != nil {
+		return nil, err
+	}
+	return NewField(name, t), nil
+}
+
+func (p *dslParser) fieldType() (Type, error) {
+	if p.peek() == '{' {
+		p.pos++
+		schema, err := p.fields('}')
+		if err != nil {
+			return nil, err
+		}
+		p.pos++
+		return schema, nil
+	}
+	start := p.pos
+	name := p.identifier()
+	if name == "" {
+		if p.pos >= len(p.text) {
+			return nil, p.errorf("expected a type but the text ended")
+		}
+		return nil, p.errorf("expected a type but found '%c'", p.peek())
+	}
+	p.skipSpaces()
+	if p.peek() != '(' {
+		if name == "TAGGED_FIELDS" {
+			return NewTaggedFields(nil), nil
+		}
+		if t, ok := dslPrimitiveTypes[name]; ok {
+			return t, nil
+		}
+		return nil, &ParseError{Offset: start, Msg: fmt.Sprintf("unknown type %s, expected one of %s, TAGGED_FIELDS or an array",
+			name, strings.Join(dslTypeNames(), ", "))}
+	}
+	p.pos++
+	var t Type
+	var err error
+	switch name {
+	case "ARRAY", "NULLABLE_ARRAY", "COMPACT_ARRAY", "COMPACT_NULLABLE_ARRAY":
+		t, err = p.array(name)
+	case "TAGGED_FIELDS":
+		t, err = p.taggedFields()
+	default:
+		return nil, &ParseError{Offset: start, Msg: fmt.Sprintf("unknown type %s(...)", name)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(')'); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (p *dslParser) array(name string) (Type, error) {
+	var element Type
+	var err error
+	if p.isField() {
+		element, err = p.fields(')')
+	} else {
+		p.skipSpaces()
+		element, err = p.fieldType()
+	}
+	if err != nil {
+		return nil, err
+	}
+	nullable := strings.Contains(name, "NULLABLE")
+	if strings.HasPrefix(name, "COMPACT_") {
+		return NewCompactArrayOf1(element, nullable), nil
+	}
+	return NewArrayOf1(element, nullable), nil
+}
+
+func (p *dslParser) taggedFields() (Type, error) {
+	fields := make(map[int]*Field)
+	for {
+		p.skipSeparators()
+		if p.pos >= len(p.text) || p.peek() == ')' {
+			break
+		}
+		start := p.pos
+		tag, err := strconv.Atoi(p.identifier())
+		if err != nil || tag < 0 {
+			return nil, &ParseError{Offset: start, Msg: "expected a tag"}
+		}
+		if _, ok := fields[tag]; ok {
+			return nil, &ParseError{Offset: start, Msg: fmt.Sprintf("tag %d is declared twice", tag)}
+		}
+		if err = p.expect('='); err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if fields[tag], err = p.field(); err != nil {
+			return nil, err
+		}
+	}
+	return NewTaggedFields(fields), nil
+}
+
+// FormatSchema writes the schema in the schema DSL, ParseSchema of the text returns a
+// schema with the same fields and types
+func FormatSchema(schema *Schema) (string, error) {
+	var sb strings.Builder
+	if err := formatFields(&sb, schema, " "); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func formatFields(sb *strings.Builder, schema *Schema, sep string) error {
+	for i, f := range schema.fields {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(f.def.name + ":")
+		if err := formatType(sb, f.def.t); err != nil {
+			return fmt.Errorf("field %s: %v", f.def.name, err)
+		}
+	}
+	return nil
+}
+
+func formatType(sb *strings.Builder, t Type) error {
+	switch tt := t.(type) {
+	case *Schema:
+		sb.WriteString("{")
+		if err := formatFields(sb, tt, ", "); err != nil {
+			return err
+		}
+		sb.WriteString("}")
+		return nil
+	case *ArrayOf:
+		return formatArray(sb, "ARRAY", tt.nullable, tt.t)
+	case *CompactArrayOf:
+		return formatArray(sb, "COMPACT_ARRAY", tt.nullable, tt.t)
+	case *TaggedFields:
+		sb.WriteString("TAGGED_FIELDS")
+		if len(tt.fields) == 0 {
+			return nil
+		}
+		sb.WriteString("(")
+		for i, tag := range tt.Tags() {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			f := tt.fields[tag]
+			sb.WriteString(strconv.Itoa(tag) + "=" + f.name + ":")
+			if err := formatType(sb, f.t); err != nil {
+				return err
+			}
+		}
+		sb.WriteString(")")
+		return nil
+	}
+	name := TypeName(t)
+	if primitive, ok := dslPrimitiveTypes[name]; !ok || primitive != t {
+		return fmt.Errorf("type %s has no DSL name", name)
+	}
+	sb.WriteString(name)
+	return nil
+}
+
+func formatArray(sb *strings.Builder, name string, nullable bool, element Type) error {
+	if nullable {
+		name = strings.Replace(name, "ARRAY", "NULLABLE_ARRAY", 1)
+	}
+	sb.WriteString(name + "(")
+	var err error
+	if schema, ok := element.(*Schema); ok && len(schema.fields) > 0 {
+		err = formatFields(sb, schema, ", ")
+	} else {
+		err = formatType(sb, element)
+	}
+	sb.WriteString(")")
+	return err
+}
+
+// dslTypeNames returns the names of the primitive types of the DSL in order
+func dslTypeNames() []string {
+	names := make([]string, 0, len(dslPrimitiveTypes))
+	for name := range dslPrimitiveTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
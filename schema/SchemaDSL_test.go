@@ -0,0 +1,82 @@
+package kafkaschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseAndFormatSchema(t *testing.T) {
+	tests := []struct {
+		text   string
+		string string
+	}{
+		{text: "group:STRING topic:STRING partition:INT32", string: "{group:STRING,topic:STRING,partition:INT32}"},
+		{text: "members:ARRAY(member_id:STRING, assignment:BYTES)", string: "{members:ARRAY({member_id:STRING,assignment:BYTES})}"},
+		{text: "ids:COMPACT_NULLABLE_ARRAY(INT32) leader:{id:STRING}", string: "{ids:COMPACT_ARRAY(INT32),leader:{id:STRING}}"},
+		{text: "name:COMPACT_STRING _tagged_fields:TAGGED_FIELDS(0=epoch:INT32, 3=host:COMPACT_NULLABLE_STRING)",
+			string: "{name:COMPACT_STRING,_tagged_fields:TAGGED_FIELDS}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			sch, err := ParseSchema(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sch.String(); got != tt.string {
+				t.Errorf("ParseSchema().String() = %s, want %s", got, tt.string)
+			}
+			text, err := FormatSchema(sch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if text != tt.text {
+				t.Errorf("FormatSchema() = %s, want %s", text, tt.text)
+			}
+		})
+	}
+}
+
+func TestParseSchemaString(t *testing.T) {
+	text := "{group:STRING,members:ARRAY({id:STRING,epoch:INT32}),leader:{id:STRING}}"
+	sch, err := ParseSchema(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sch.String(); got != text {
+		t.Errorf("ParseSchema(%s).String() = %s", text, got)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		text   string
+		offset int
+		msg    string
+	}{
+		{text: "name", offset: 4, msg: "expected ':' but the text ended"},
+		{text: "name:", offset: 5, msg: "expected a type but the text ended"},
+		{text: "name:FOO", offset: 5, msg: "unknown type FOO"},
+		{text: "a:ARRAY(STRING", offset: 14, msg: "expected ')' but the text ended"},
+		{text: "a:ARRAY()", offset: 8, msg: "expected a type but found ')'"},
+		{text: "{a:STRING", offset: 9, msg: "expected '}' but the text ended"},
+		{text: "a:{b:INT32", offset: 10, msg: "expected '}' but the text ended"},
+		{text: "a:STRING}", offset: 8, msg: "expected a field name but found '}'"},
+		{text: "a:STRING a:INT32", offset: 0, msg: "duplicate field: a"},
+		{text: "t:TAGGED_FIELDS(x=a:INT32)", offset: 16, msg: "expected a tag"},
+		{text: "t:TAGGED_FIELDS(1=a:INT32, 1=b:INT32)", offset: 27, msg: "tag 1 is declared twice"},
+		{text: "t:TAGGED_FIELDS a:INT32", offset: 0, msg: "the tagged fields section must be the last field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			sch, err := ParseSchema(tt.text)
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("ParseSchema() = %v, %v, want a *ParseError", sch, err)
+			}
+			if pe.Offset != tt.offset || !strings.Contains(pe.Msg, tt.msg) {
+				t.Errorf("ParseSchema() error = %v, want %q at offset %d", err, tt.msg, tt.offset)
+			}
+		})
+	}
+}
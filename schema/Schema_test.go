@@ -0,0 +1,140 @@
+package kafkaschema
+
+import (
+	"encoding/hex"
+	"kafka_schema/schema/buffer"
+	"math"
+	"testing"
+)
+
+func TestSerializeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		t     Type
+		value interface{}
+		want  string
+	}{
+		{name: "INT16", t: INT16, value: int16(-2), want: "fffe"},
+		{name: "INT32", t: INT32, value: int32(258), want: "00000102"},
+		{name: "INT64", t: INT64, value: int64(-1), want: "ffffffffffffffff"},
+		{name: "VARINT", t: VARINT, value: int32(-65), want: "8101"},
+		{name: "VARLONG", t: VARLONG, value: int64(math.MinInt64), want: "ffffffffffffffffff01"},
+		{name: "UNSIGNED_VARINT", t: UnsignedVarint, value: int32(300), want: "ac02"},
+		{name: "STRING", t: STRING, value: "ab", want: "00026162"},
+		{name: "empty STRING", t: STRING, value: "", want: "0000"},
+		{name: "NULLABLE_STRING", t: NullableString, value: "ab", want: "00026162"},
+		{name: "null NULLABLE_STRING", t: NullableString, value: nil, want: "ffff"},
+		{name: "BYTES", t: BYTES, value: buffer.Wrap([]byte{1, 2}), want: "000000020102"},
+		{name: "null NULLABLE_BYTES", t: NullableBytes, value: nil, want: "ffffffff"},
+		{name: "ARRAY", t: NewArrayOf(INT32), value: []interface{}{int32(1), int32(2)}, want: "000000020000000100000002"},
+		{name: "empty ARRAY", t: NewArrayOf(STRING), value: []interface{}{}, want: "00000000"},
+		{name: "null ARRAY", t: NewArrayOf1(INT32, true), value: nil, want: "ffffffff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := Serialize(tt.t, tt.value)
+			if err != nil {
+				t.Fatalf("Serialize() error = %v", err)
+			}
+			if got := hex.EncodeToString(buf.Bytes()); got != tt.want {
+				t.Errorf("Serialize() = %s, want %s", got, tt.want)
+			}
+			read, err := tt.t.Read(buf, nil)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !valueEqual(read, tt.value) {
+				t.Errorf("Read() = %v, want %v", read, tt.value)
+			}
+			if buf.HasRemaining() {
+				t.Errorf("Read() left %d bytes", buf.Remaining())
+			}
+		})
+	}
+}
+
+func TestStructWriteRoundTrip(t *testing.T) {
+	inner, err := NewSchema(NewField("id", INT32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := NewSchema(
+		NewField("name", STRING),
+		NewField("note", NullableString),
+		NewField("items", NewArrayOf(inner)),
+		NewField1("count", INT32, "", int32(7)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := NewStruct1(inner)
+	if err := item.Set("id", int32(5)); err != nil {
+		t.Fatal(err)
+	}
+	ks := NewStruct1(sch)
+	if err := ks.Set("name", "group"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Set("items", []interface{}{item}); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := Serialize(sch, ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(buf.Bytes()), "000567726f7570"+"ffff"+"00000001"+"00000005"+"00000007"; got != want {
+		t.Errorf("Serialize() = %s, want %s", got, want)
+	}
+	read, err := sch.Read(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !read.(*Struct).Equal(ks) {
+		t.Errorf("Read() = %v, want %v", read, ks)
+	}
+}
+
+func TestStructWriteMissingValue(t *testing.T) {
+	sch, err := NewSchema(NewField("name", STRING))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Serialize(sch, NewStruct1(sch)); err == nil {
+		t.Error("Serialize() of a struct missing a value without default succeeded")
+	}
+}
+
+func TestWriteWrongType(t *testing.T) {
+	tests := []struct {
+		t          Type
+		name       string
+		checksSize bool
+	}{
+		{t: INT16, name: "INT16"},
+		{t: INT32, name: "INT32"},
+		{t: INT64, name: "INT64"},
+		{t: STRING, name: "STRING", checksSize: true},
+		{t: NullableString, name: "NULLABLE_STRING", checksSize: true},
+		{t: CompactString, name: "COMPACT_STRING", checksSize: true},
+		{t: CompactNullableString, name: "COMPACT_NULLABLE_STRING", checksSize: true},
+		{t: BYTES, name: "BYTES", checksSize: true},
+		{t: NullableBytes, name: "NULLABLE_BYTES", checksSize: true},
+		{t: CompactBytes, name: "COMPACT_BYTES", checksSize: true},
+		{t: CompactNullableBytes, name: "COMPACT_NULLABLE_BYTES", checksSize: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := "true is not a " + tt.name
+			if err := tt.t.Write(buffer.AllocateExpandable(8), true); err == nil || err.Error() != want {
+				t.Errorf("Write() error = %v, want %s", err, want)
+			}
+			if !tt.checksSize {
+				return
+			}
+			if _, err := tt.t.SizeOf(true); err == nil || err.Error() != want {
+				t.Errorf("SizeOf() error = %v, want %s", err, want)
+			}
+		})
+	}
+}
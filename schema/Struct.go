@@ -3,17 +3,122 @@ package kafkaschema
 import (
 	"fmt"
 	"kafka_schema/schema/buffer"
-	"kafka_schema/util"
-	"reflect"
+	"sort"
 )
 
 type Struct struct {
 	schema *Schema
 	values []interface{}
+	// set tells the fields holding a value apart from the unset fields, the value of a set
+	// field is kept even when it is null while an unset field reads as its default
+	set []bool
 }
 
+// NewStruct returns a Struct holding the values in the order of the fields of the schema,
+// the nil values leave their field unset
 func NewStruct(schema *Schema, values []interface{}) *Struct {
-	return &Struct{schema: schema, values: values}
+	newTaggedFieldsValue(schema, values)
+	set := make([]bool, len(values))
+	for i, v := range values {
+		set[i] = v != nil
+	}
+	return &Struct{schema: schema, values: values, set: set}
+}
+
+// newReadStruct returns a Struct of the values read from a buffer, every field is set and
+// a nil value is a null
+func newReadStruct(schema *Schema, values []interface{}) *Struct {
+	newTaggedFieldsValue(schema, values)
+	set := make([]bool, len(values))
+	for i := range set {
+		set[i] = true
+	}
+	return &Struct{schema: schema, values: values, set: set}
+}
+
+// newTaggedFieldsValue gives the tagged fields section of the values its own map, a read
+// of a struct does not modify it
+func newTaggedFieldsValue(schema *Schema, values []interface{}) {
+	if schema == nil || schema.taggedFields == nil || schema.taggedFields.index >= len(values) {
+		return
+	}
+	if values[schema.taggedFields.index] == nil {
+		values[schema.taggedFields.index] = map[int]interface{}{}
+	}
+}
+
+// NewStruct1 returns a Struct whose fields are set to their default values, the fields
+// without default are left unset and must be set before the Struct is written
+func NewStruct1(schema *Schema) *Struct {
+	values := make([]interface{}, len(schema.fields))
+	for i, field := range schema.fields {
+		if field.def.hasDefaultValue {
+			values[i] = field.def.defaultValue
+		}
+	}
+	return NewStruct(schema, values)
+}
+
+// Set sets the value of the field name, the value is validated against the type of the field.
+// A nil value sets a nullable field to null, Unset brings a field back to its default value.
+func (ks *Struct) Set(name string, value interface{}) error {
+	field, err := ks.schema.Get(name)
+	if err != nil {
+		return err
+	}
+	return ks.SetByField(field, value)
+}
+
+func (ks *Struct) SetByField(field *BoundField, value interface{}) error {
+	if err := ks.validateField(field); err != nil {
+		return err
+	}
+	v, err := field.def.t.Validate(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for field '%s': %v", field.def.name, err)
+	}
+	ks.setValue(field, v)
+	return nil
+}
+
+// Unset unsets the field name, it reads as its default value until it is set again
+func (ks *Struct) Unset(name string) error {
+	field, err := ks.schema.Get(name)
+	if err != nil {
+		return err
+	}
+	if err := ks.validateField(field); err != nil {
+		return err
+	}
+	ks.values[field.index] = nil
+	if field.index < len(ks.set) {
+		ks.set[field.index] = false
+	}
+	return nil
+}
+
+// IsSet returns true if the field name holds a value, which may be null, rather than its default
+func (ks *Struct) IsSet(name string) bool {
+	field, err := ks.schema.Get(name)
+	return err == nil && ks.isSet(field)
+}
+
+func (ks *Struct) setValue(field *BoundField, value interface{}) {
+	if len(ks.set) < len(ks.values) {
+		set := make([]bool, len(ks.values))
+		copy(set, ks.set)
+		ks.set = set
+	}
+	ks.values[field.index] = value
+	ks.set[field.index] = true
+}
+
+func (ks *Struct) isSet(field *BoundField) bool {
+	return field.index < len(ks.set) && ks.set[field.index]
+}
+
+func (ks *Struct) Schema() *Schema {
+	return ks.schema
 }
 
 func (ks *Struct) GetField(field *BoundField) (interface{}, error) {
@@ -24,11 +129,21 @@ func (ks *Struct) GetField(field *BoundField) (interface{}, error) {
 	return ks.getFieldOrDefault(field)
 }
 
+// SizeOf returns the size in bytes of the struct serialized with its schema
+func (ks *Struct) SizeOf() (int, error) {
+	return ks.schema.SizeOf(ks)
+}
+
+// WriteTo serializes the struct with its schema into the buffer
+func (ks *Struct) WriteTo(buffer *buffer.ByteBuffer) error {
+	return ks.schema.Write(buffer, ks)
+}
+
 func (ks *Struct) validateField(field *BoundField) error {
 	if ks.schema != field.schema {
 		return fmt.Errorf("attempt to access field '%s' from a different schema instance", field.def.name)
 	}
-	if field.index > len(ks.values) {
+	if field.index >= len(ks.values) {
 		return fmt.Errorf("invalid field index: %d", field.index)
 	}
 	return nil
@@ -36,7 +151,12 @@ func (ks *Struct) validateField(field *BoundField) error {
 
 func (ks *Struct) getFieldOrDefault(field *BoundField) (interface{}, error) {
 	v := ks.values[field.index]
-	if v != nil {
+	if v == nil && field == ks.schema.taggedFields {
+		// the tagged fields of an unset section are empty, they are not shared through a default
+		return map[int]interface{}{}, nil
+	}
+	if v != nil || ks.isSet(field) && field.def.t.isNullable() {
+		// a value read or set to null stays null, the default is the value of an unset field
 		return v, nil
 	} else if field.def.hasDefaultValue {
 		return field.def.defaultValue, nil
@@ -47,73 +167,97 @@ func (ks *Struct) getFieldOrDefault(field *BoundField) (interface{}, error) {
 	}
 }
 
+// GetString returns the value of a string field, a null value is returned as an empty string
 func (ks Struct) GetString(name string) (string, error) {
-	f, err := ks.get(name)
-	if err != nil {
+	s, err := GetOptional[string](&ks, name)
+	if err != nil || s == nil {
 		return "", err
 	}
-	interface2String, err := util.Interface2String(f)
-	return interface2String, err
+	return *s, nil
 }
 
 func (ks Struct) GetStringByField(field *BoundField) (string, error) {
 	f, err := ks.getByField(field)
-	if err != nil {
+	if err != nil || f == nil {
 		return "", err
 	}
-	return util.Interface2String(f)
+	return convertValue[string](field.def.name, f)
+}
+
+func (ks Struct) GetBool(name string) (bool, error) {
+	return Get[bool](&ks, name)
+}
+
+func (ks Struct) GetBoolByField(field *BoundField) (bool, error) {
+	return GetByField[bool](&ks, field)
+}
+
+func (ks Struct) GetInt8(name string) (int8, error) {
+	return Get[int8](&ks, name)
+}
+
+func (ks Struct) GetInt8ByField(field *BoundField) (int8, error) {
+	return GetByField[int8](&ks, field)
+}
+
+func (ks Struct) GetUint16(name string) (uint16, error) {
+	return Get[uint16](&ks, name)
+}
+
+func (ks Struct) GetUint16ByField(field *BoundField) (uint16, error) {
+	return GetByField[uint16](&ks, field)
+}
+
+func (ks Struct) GetUint32(name string) (uint32, error) {
+	return Get[uint32](&ks, name)
+}
+
+func (ks Struct) GetUint32ByField(field *BoundField) (uint32, error) {
+	return GetByField[uint32](&ks, field)
+}
+
+func (ks Struct) GetFloat64(name string) (float64, error) {
+	return Get[float64](&ks, name)
+}
+
+func (ks Struct) GetFloat64ByField(field *BoundField) (float64, error) {
+	return GetByField[float64](&ks, field)
+}
+
+func (ks Struct) GetUuid(name string) (Uuid, error) {
+	return Get[Uuid](&ks, name)
+}
+
+func (ks Struct) GetUuidByField(field *BoundField) (Uuid, error) {
+	return GetByField[Uuid](&ks, field)
 }
 
 func (ks Struct) GetInt16(name string) (int16, error) {
-	f, err := ks.get(name)
-	if err != nil {
-		return 0, err
-	}
-	return util.Interface2Int16(f)
+	return Get[int16](&ks, name)
 }
 
+// GetInt returns the value of an INT32 field as an int
 func (ks Struct) GetInt(name string) (int, error) {
-	f, err := ks.get(name)
-	if err != nil {
-		return 0, err
-	}
-	return util.Interface2Int(f)
+	i, err := Get[int32](&ks, name)
+	return int(i), err
 }
 
 func (ks Struct) GetIntByField(field *BoundField) (int, error) {
-	f, err := ks.getByField(field)
-	if err != nil {
-		return 0, err
-	}
-	return util.Interface2Int(f)
+	i, err := GetByField[int32](&ks, field)
+	return int(i), err
 }
 
 func (ks Struct) GetInt64(name string) (int64, error) {
-	f, err := ks.get(name)
-	if err != nil {
-		return 0, err
-	}
-	return util.Interface2Int64(f)
+	return Get[int64](&ks, name)
 }
 
 func (ks Struct) GetInt64ByField(field *BoundField) (int64, error) {
-	f, err := ks.getByField(field)
-	if err != nil {
-		return 0, err
-	}
-	return util.Interface2Int64(f)
+	return GetByField[int64](&ks, field)
 }
 
+// GetByteBuffer returns the value of a bytes field, a null value is returned as nil
 func (ks Struct) GetByteBuffer(name string) (*buffer.ByteBuffer, error) {
-	f, err := ks.get(name)
-	if err != nil {
-		return nil, err
-	}
-	if f != nil {
-		return f.(*buffer.ByteBuffer), nil
-	} else {
-		return nil, nil
-	}
+	return ks.GetBytes(name)
 }
 
 func (ks Struct) getByField(field *BoundField) (interface{}, error) {
@@ -132,43 +276,60 @@ func (ks Struct) get(name string) (interface{}, error) {
 	return ks.getFieldOrDefault(boundField)
 }
 
+// GetArray returns the elements of an array field, a null array is returned as nil
 func (ks Struct) GetArray(name string) ([]interface{}, error) {
 	f, err := ks.get(name)
-	if err != nil {
+	if err != nil || f == nil {
 		return nil, err
 	}
-	if f == nil {
-		return nil, nil
-	}
-
-	if partition, ok := f.(int32); ok {
-		result := make([]interface{}, 0)
-		result = append(result, partition)
-		return result, nil
-	}
-
-	if _, ok := f.([]interface{}); ok {
-		return f.([]interface{}), nil
-	} else {
-		return nil, nil
-	}
+	return convertValue[[]interface{}](name, f)
 }
 
+// HasField returns whether the schema of the struct has a field with the given name
 func (ks Struct) HasField(name string) bool {
 	_, err := ks.schema.Get(name)
-	return err != nil
+	return err == nil
 }
 
+// GetBytes returns the value of a bytes field, a null value is returned as nil
 func (ks Struct) GetBytes(name string) (*buffer.ByteBuffer, error) {
-	f, err := ks.get(name)
-	if err != nil {
+	b, err := GetOptional[*buffer.ByteBuffer](&ks, name)
+	if err != nil || b == nil {
 		return nil, err
 	}
+	return *b, nil
+}
 
-	if reflect.TypeOf(f).Kind() != reflect.Slice || reflect.TypeOf(f).Kind() != reflect.Array {
-		return f.(*buffer.ByteBuffer), nil
+// GetTaggedField returns the value of the tagged field with the given tag, unknown tags
+// are returned as a *RawTaggedField
+func (ks *Struct) GetTaggedField(tag int) (interface{}, bool) {
+	objects := ks.taggedFields()
+	obj, ok := objects[tag]
+	return obj, ok
+}
+
+// UnknownTaggedFields returns the tagged fields not declared by the schema ordered by tag
+func (ks *Struct) UnknownTaggedFields() []*RawTaggedField {
+	raws := make([]*RawTaggedField, 0)
+	for _, obj := range ks.taggedFields() {
+		if raw, ok := obj.(*RawTaggedField); ok {
+			raws = append(raws, raw)
+		}
 	}
+	sort.Slice(raws, func(i, j int) bool {
+		return raws[i].Tag < raws[j].Tag
+	})
+	return raws
+}
 
-	bs := []byte(fmt.Sprintf("%v", f))
-	return buffer.NewByteBuffer(bs), err
+func (ks *Struct) taggedFields() map[int]interface{} {
+	if !ks.schema.IsFlexible() {
+		return nil
+	}
+	f, err := ks.getFieldOrDefault(ks.schema.taggedFields)
+	if err != nil {
+		return nil
+	}
+	objects, _ := f.(map[int]interface{})
+	return objects
 }
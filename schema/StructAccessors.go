@@ -0,0 +1,82 @@
+package kafkaschema
+
+import (
+	"fmt"
+)
+
+// Get returns the value of the named field as a T, a null value is an error, nullable
+// fields are read with GetOptional
+func Get[T any](ks *Struct, name string) (T, error) {
+	var zero T
+	v, err := ks.get(name)
+	if err != nil {
+		return zero, err
+	}
+	return convertValue[T](name, v)
+}
+
+// GetByField returns the value of the bound field as a T, a null value is an error
+func GetByField[T any](ks *Struct, field *BoundField) (T, error) {
+	var zero T
+	v, err := ks.getByField(field)
+	if err != nil {
+		return zero, err
+	}
+	return convertValue[T](field.def.name, v)
+}
+
+// GetOptional returns the value of the named field as a *T, a null value is returned as nil
+func GetOptional[T any](ks *Struct, name string) (*T, error) {
+	v, err := ks.get(name)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	t, err := convertValue[T](name, v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetArrayOf returns the elements of the named array field as a []T, a null array is
+// returned as nil and a null element is an error
+func GetArrayOf[T any](ks *Struct, name string) ([]T, error) {
+	values, err := ks.GetArray(name)
+	if err != nil || values == nil {
+		return nil, err
+	}
+	result := make([]T, len(values))
+	for i, v := range values {
+		if result[i], err = convertValue[T](fmt.Sprintf("%s[%d]", name, i), v); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// GetStructArray returns the elements of an array of structs, a null array is returned as nil
+func (ks *Struct) GetStructArray(name string) ([]*Struct, error) {
+	return GetArrayOf[*Struct](ks, name)
+}
+
+// GetInt32Array returns the elements of an array of INT32, a null array is returned as nil
+func (ks *Struct) GetInt32Array(name string) ([]int32, error) {
+	return GetArrayOf[int32](ks, name)
+}
+
+// GetStringArray returns the elements of an array of strings, a null array is returned as nil
+func (ks *Struct) GetStringArray(name string) ([]string, error) {
+	return GetArrayOf[string](ks, name)
+}
+
+func convertValue[T any](name string, v interface{}) (T, error) {
+	var zero T
+	if v == nil {
+		return zero, fmt.Errorf("field '%s' is null", name)
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: field '%s' is a %T, not a %T", ErrTypeMismatch, name, v, zero)
+	}
+	return t, nil
+}
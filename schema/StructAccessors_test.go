@@ -0,0 +1,53 @@
+package kafkaschema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStructGenericAccessors(t *testing.T) {
+	ks := newPathStruct(t)
+	members, err := ks.GetStructArray("members")
+	if err != nil || len(members) != 2 {
+		t.Fatalf("GetStructArray(members) = %v, %v, want two members", members, err)
+	}
+	m1, m2 := members[0], members[1]
+
+	if id, err := Get[string](m1, "id"); err != nil || id != "m1" {
+		t.Errorf("Get(id) = %q, %v, want m1", id, err)
+	}
+	if _, err := Get[int32](m1, "id"); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Get[int32](id) error = %v, want %v", err, ErrTypeMismatch)
+	}
+	if _, err := Get[string](m2, "host"); err == nil {
+		t.Error("Get() of a null value succeeded")
+	}
+	if _, err := Get[string](m1, "unknown"); err == nil {
+		t.Error("Get() of an unknown field succeeded")
+	}
+	field, err := m1.Schema().Get("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := GetByField[string](m2, field); err != nil || id != "m2" {
+		t.Errorf("GetByField(id) = %q, %v, want m2", id, err)
+	}
+
+	if host, err := GetOptional[string](m1, "host"); err != nil || host == nil || *host != "h1" {
+		t.Errorf("GetOptional(host) = %v, %v, want h1", host, err)
+	}
+	if host, err := GetOptional[string](m2, "host"); err != nil || host != nil {
+		t.Errorf("GetOptional(host) of a null = %v, %v, want nil", host, err)
+	}
+
+	if topics, err := m1.GetStringArray("topics"); err != nil || !reflect.DeepEqual(topics, []string{"t1", "t2"}) {
+		t.Errorf("GetStringArray(topics) = %v, %v, want [t1 t2]", topics, err)
+	}
+	if topics, err := GetArrayOf[string](m2, "topics"); err != nil || topics != nil {
+		t.Errorf("GetArrayOf(topics) of a null array = %v, %v, want nil", topics, err)
+	}
+	if _, err := m1.GetInt32Array("topics"); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("GetInt32Array(topics) error = %v, want %v", err, ErrTypeMismatch)
+	}
+}
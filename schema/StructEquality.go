@@ -0,0 +1,231 @@
+package kafkaschema
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"kafka_schema/schema/buffer"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Equal returns true if both structs have the same fields with the same values. The values
+// are compared by content: bytes by their remaining bytes whatever the buffer positions,
+// nested structs and arrays element by element, and unset fields by their default value.
+// The schemas may be different instances with the same field names and types.
+func (ks *Struct) Equal(other *Struct) bool {
+	if ks == other {
+		return true
+	}
+	if ks == nil || other == nil || !sameFields(ks.schema, other.schema) {
+		return false
+	}
+	for i, field := range ks.schema.fields {
+		if !valueEqual(ks.valueOrNil(field), other.valueOrNil(other.schema.fields[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns a hash of the content of the struct, equal structs have the same hash
+func (ks *Struct) Hash() uint64 {
+	h := fnv.New64a()
+	hashValue(h, ks)
+	return h.Sum64()
+}
+
+// Clone returns a deep copy of the struct, the nested structs, arrays and bytes are copied
+// so that the copy shares no mutable value with the struct
+func (ks *Struct) Clone() *Struct {
+	values := make([]interface{}, len(ks.values))
+	for i, v := range ks.values {
+		values[i] = cloneValue(v)
+	}
+	set := make([]bool, len(ks.set))
+	copy(set, ks.set)
+	return &Struct{schema: ks.schema, values: values, set: set}
+}
+
+// valueOrNil returns the value of the field or its default, nil when it has none
+func (ks *Struct) valueOrNil(field *BoundField) interface{} {
+	if field.index >= len(ks.values) {
+		return nil
+	}
+	v, err := ks.getFieldOrDefault(field)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+func sameFields(a, b *Schema) bool {
+	if a == b {
+		return true
+	}
+	if len(a.fields) != len(b.fields) {
+		return false
+	}
+	for i := range a.fields {
+		if a.fields[i].def.name != b.fields[i].def.name || TypeName(a.fields[i].def.t) != TypeName(b.fields[i].def.t) {
+			return false
+		}
+	}
+	return true
+}
+
+func valueEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch va := a.(type) {
+	case *Struct:
+		vb, ok := b.(*Struct)
+		return ok && va.Equal(vb)
+	case []interface{}:
+		vb, ok := b.([]interface{})
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for i := range va {
+			if !valueEqual(va[i], vb[i]) {
+				return false
+			}
+		}
+		return true
+	case *buffer.ByteBuffer:
+		vb, ok := b.(*buffer.ByteBuffer)
+		return ok && string(va.Bytes()) == string(vb.Bytes())
+	case map[int]interface{}:
+		vb, ok := b.(map[int]interface{})
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for tag, obj := range va {
+			other, ok := vb[tag]
+			if !ok || !valueEqual(obj, other) {
+				return false
+			}
+		}
+		return true
+	case *RawTaggedField:
+		vb, ok := b.(*RawTaggedField)
+		return ok && va.Tag == vb.Tag && string(va.Data) == string(vb.Data)
+	default:
+		if !reflect.TypeOf(a).Comparable() {
+			return reflect.DeepEqual(a, b)
+		}
+		return a == b
+	}
+}
+
+// the kinds of values written to the hash before the values
+const (
+	hashNil byte = iota
+	hashStruct
+	hashArray
+	hashBytes
+	hashTaggedFields
+	hashRawTaggedField
+	hashBool
+	hashInt
+	hashUint
+	hashFloat
+	hashString
+	hashUuid
+	hashOther
+)
+
+func hashValue(h hash.Hash64, v interface{}) {
+	var b [9]byte
+	writeUint := func(kind byte, u uint64) {
+		b[0] = kind
+		binary.BigEndian.PutUint64(b[1:], u)
+		_, _ = h.Write(b[:])
+	}
+	writeBytes := func(kind byte, bs []byte) {
+		writeUint(kind, uint64(len(bs)))
+		_, _ = h.Write(bs)
+	}
+	switch value := v.(type) {
+	case nil:
+		writeUint(hashNil, 0)
+	case *Struct:
+		writeUint(hashStruct, uint64(len(value.schema.fields)))
+		for _, field := range value.schema.fields {
+			writeBytes(hashString, []byte(field.def.name))
+			hashValue(h, value.valueOrNil(field))
+		}
+	case []interface{}:
+		writeUint(hashArray, uint64(len(value)))
+		for _, obj := range value {
+			hashValue(h, obj)
+		}
+	case *buffer.ByteBuffer:
+		writeBytes(hashBytes, value.Bytes())
+	case map[int]interface{}:
+		tags := make([]int, 0, len(value))
+		for tag := range value {
+			tags = append(tags, tag)
+		}
+		sort.Ints(tags)
+		writeUint(hashTaggedFields, uint64(len(tags)))
+		for _, tag := range tags {
+			writeUint(hashInt, uint64(tag))
+			hashValue(h, value[tag])
+		}
+	case *RawTaggedField:
+		writeUint(hashRawTaggedField, uint64(value.Tag))
+		writeBytes(hashBytes, value.Data)
+	case bool:
+		if value {
+			writeUint(hashBool, 1)
+		} else {
+			writeUint(hashBool, 0)
+		}
+	case string:
+		writeBytes(hashString, []byte(value))
+	case float64:
+		writeUint(hashFloat, math.Float64bits(value))
+	case Uuid:
+		writeBytes(hashUuid, value[:])
+	default:
+		rv := reflect.ValueOf(v)
+		switch {
+		case isSigned(rv.Kind()):
+			writeUint(hashInt+byte(rv.Kind())<<4, uint64(rv.Int()))
+		case isUnsigned(rv.Kind()):
+			writeUint(hashUint+byte(rv.Kind())<<4, rv.Uint())
+		default:
+			writeBytes(hashOther, []byte(rv.Type().String()))
+		}
+	}
+}
+
+func cloneValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case *Struct:
+		return value.Clone()
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, obj := range value {
+			array[i] = cloneValue(obj)
+		}
+		return array
+	case *buffer.ByteBuffer:
+		return buffer.NewByteBuffer(value.Bytes())
+	case map[int]interface{}:
+		objects := make(map[int]interface{}, len(value))
+		for tag, obj := range value {
+			objects[tag] = cloneValue(obj)
+		}
+		return objects
+	case *RawTaggedField:
+		data := make([]byte, len(value.Data))
+		copy(data, value.Data)
+		return &RawTaggedField{Tag: value.Tag, Data: data}
+	default:
+		return v
+	}
+}
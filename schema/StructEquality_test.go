@@ -0,0 +1,125 @@
+package kafkaschema
+
+import (
+	"kafka_schema/schema/buffer"
+	"testing"
+)
+
+func newEqualitySchema(t *testing.T) *Schema {
+	t.Helper()
+	member, err := NewSchema(NewField("id", STRING))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := NewSchema(
+		NewField("name", STRING),
+		NewField1("epoch", INT32, "", int32(-1)),
+		NewField("data", BYTES),
+		NewField("members", NewArrayOf(member)),
+		NewTaggedFieldsSection(nil),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sch
+}
+
+// newEqualityStruct returns a struct of the schema, the bytes of data start at its position
+func newEqualityStruct(sch *Schema, epoch, data interface{}, ids []string, tagged map[int]interface{}) *Struct {
+	member := sch.fields[3].def.t.(*ArrayOf).t.(*Schema)
+	members := make([]interface{}, len(ids))
+	for i, id := range ids {
+		members[i] = NewStruct(member, []interface{}{id})
+	}
+	return NewStruct(sch, []interface{}{"g", epoch, data, members, tagged})
+}
+
+func TestStructEqualAndHash(t *testing.T) {
+	sch := newEqualitySchema(t)
+	other := newEqualitySchema(t)
+	offset := buffer.Wrap([]byte{0xff, 0xaa, 0xbb})
+	_ = offset.SetPosition(1)
+	base := newEqualityStruct(sch, int32(3), buffer.Wrap([]byte{0xaa, 0xbb}), []string{"m1", "m2"},
+		map[int]interface{}{2: &RawTaggedField{Tag: 2, Data: []byte{1}}, 5: &RawTaggedField{Tag: 5, Data: []byte{2}}})
+
+	tests := []struct {
+		name  string
+		other *Struct
+		equal bool
+	}{
+		{name: "same content in another schema instance", equal: true, other: newEqualityStruct(other, int32(3), buffer.Wrap([]byte{0xaa, 0xbb}), []string{"m1", "m2"},
+			map[int]interface{}{2: &RawTaggedField{Tag: 2, Data: []byte{1}}, 5: &RawTaggedField{Tag: 5, Data: []byte{2}}})},
+		{name: "bytes at another buffer position", equal: true, other: newEqualityStruct(sch, int32(3), offset, []string{"m1", "m2"},
+			map[int]interface{}{2: &RawTaggedField{Tag: 2, Data: []byte{1}}, 5: &RawTaggedField{Tag: 5, Data: []byte{2}}})},
+		{name: "tagged fields added in another order", equal: true, other: func() *Struct {
+			tagged := map[int]interface{}{}
+			tagged[5] = &RawTaggedField{Tag: 5, Data: []byte{2}}
+			tagged[2] = &RawTaggedField{Tag: 2, Data: []byte{1}}
+			return newEqualityStruct(sch, int32(3), buffer.Wrap([]byte{0xaa, 0xbb}), []string{"m1", "m2"}, tagged)
+		}()},
+		{name: "other member", equal: false, other: newEqualityStruct(sch, int32(3), buffer.Wrap([]byte{0xaa, 0xbb}), []string{"m1", "m3"},
+			map[int]interface{}{2: &RawTaggedField{Tag: 2, Data: []byte{1}}, 5: &RawTaggedField{Tag: 5, Data: []byte{2}}})},
+		{name: "other bytes", equal: false, other: newEqualityStruct(sch, int32(3), buffer.Wrap([]byte{0xaa}), []string{"m1", "m2"},
+			map[int]interface{}{2: &RawTaggedField{Tag: 2, Data: []byte{1}}, 5: &RawTaggedField{Tag: 5, Data: []byte{2}}})},
+		{name: "missing tagged field", equal: false, other: newEqualityStruct(sch, int32(3), buffer.Wrap([]byte{0xaa, 0xbb}), []string{"m1", "m2"},
+			map[int]interface{}{2: &RawTaggedField{Tag: 2, Data: []byte{1}}})},
+		{name: "null bytes", equal: false, other: newEqualityStruct(sch, int32(3), nil, []string{"m1", "m2"},
+			map[int]interface{}{2: &RawTaggedField{Tag: 2, Data: []byte{1}}, 5: &RawTaggedField{Tag: 5, Data: []byte{2}}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base.Equal(tt.other); got != tt.equal {
+				t.Errorf("Equal() = %v, want %v", got, tt.equal)
+			}
+			if got := tt.other.Equal(base); got != tt.equal {
+				t.Errorf("Equal() of the other struct = %v, want %v", got, tt.equal)
+			}
+			if tt.equal && base.Hash() != tt.other.Hash() {
+				t.Errorf("Hash() = %x and %x of equal structs", base.Hash(), tt.other.Hash())
+			}
+		})
+	}
+}
+
+func TestStructEqualUnsetIsDefault(t *testing.T) {
+	sch := newEqualitySchema(t)
+	unset := newEqualityStruct(sch, nil, buffer.Wrap(nil), nil, nil)
+	if err := unset.Unset("epoch"); err != nil {
+		t.Fatal(err)
+	}
+	def := newEqualityStruct(sch, int32(-1), buffer.Wrap(nil), nil, nil)
+	if !unset.Equal(def) || unset.Hash() != def.Hash() {
+		t.Errorf("a struct with an unset field is not equal to one with its default")
+	}
+	if err := unset.Set("epoch", int32(4)); err != nil {
+		t.Fatal(err)
+	}
+	if unset.Equal(def) {
+		t.Errorf("a struct with a set field is equal to one with its default")
+	}
+}
+
+func TestStructClone(t *testing.T) {
+	sch := newEqualitySchema(t)
+	original := newEqualityStruct(sch, int32(3), buffer.Wrap([]byte{0xaa, 0xbb}), []string{"m1"},
+		map[int]interface{}{5: &RawTaggedField{Tag: 5, Data: []byte{2}}})
+	clone := original.Clone()
+	if !clone.Equal(original) || clone.Hash() != original.Hash() {
+		t.Fatalf("Clone() = %v, want a struct equal to %v", clone, original)
+	}
+
+	members, _ := clone.GetArray("members")
+	if err := members[0].(*Struct).Set("id", "m2"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := clone.GetBytes("data")
+	_ = data.PutByte(0xcc)
+	tagged, _ := Get[map[int]interface{}](clone, TaggedFieldsSectionName)
+	tagged[5].(*RawTaggedField).Data[0] = 3
+
+	want := newEqualityStruct(sch, int32(3), buffer.Wrap([]byte{0xaa, 0xbb}), []string{"m1"},
+		map[int]interface{}{5: &RawTaggedField{Tag: 5, Data: []byte{2}}})
+	if !original.Equal(want) {
+		t.Errorf("the original struct is %v after updating its clone, want %v", original, want)
+	}
+}
@@ -0,0 +1,192 @@
+package kafkaschema
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"kafka_schema/schema/buffer"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BytesEncoding is the text encoding of the BYTES values exported by ToMap and MarshalJSON
+type BytesEncoding int
+
+const (
+	Base64Encoding BytesEncoding = iota
+	HexEncoding
+)
+
+// ExportOptions configures the conversion of a Struct into a map or into JSON
+type ExportOptions struct {
+	BytesEncoding BytesEncoding
+}
+
+// ToMap converts the struct into a map from field name to value. Nested structs are
+// converted into maps, arrays into []interface{}, bytes into base64 strings and UUIDs
+// into their string form. Null values are kept as nil so that they differ from empty ones.
+func (ks *Struct) ToMap() (map[string]interface{}, error) {
+	return ks.ToMapWithOptions(ExportOptions{})
+}
+
+func (ks *Struct) ToMapWithOptions(opts ExportOptions) (map[string]interface{}, error) {
+	fields, err := exporter{opts: opts}.structFields(ks)
+	if err != nil {
+		return nil, err
+	}
+	return fields.toMap(), nil
+}
+
+// MarshalJSON encodes the struct as a JSON object whose keys are in the order of the schema
+func (ks *Struct) MarshalJSON() ([]byte, error) {
+	return ks.MarshalJSONWithOptions(ExportOptions{})
+}
+
+func (ks *Struct) MarshalJSONWithOptions(opts ExportOptions) ([]byte, error) {
+	fields, err := exporter{opts: opts, ordered: true}.structFields(ks)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// orderedFields is an exported struct that keeps the order of its fields in JSON
+type orderedFields struct {
+	names  []string
+	values []interface{}
+}
+
+func (o *orderedFields) add(name string, value interface{}) {
+	o.names = append(o.names, name)
+	o.values = append(o.values, value)
+}
+
+func (o *orderedFields) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, len(o.names))
+	for i, name := range o.names {
+		m[name] = o.values[i]
+	}
+	return m
+}
+
+func (o *orderedFields) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("{")
+	for i, name := range o.names {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, fmt.Errorf("error encoding field '%s': %v", name, err)
+		}
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(value)
+	}
+	b.WriteString("}")
+	return []byte(b.String()), nil
+}
+
+type exporter struct {
+	opts    ExportOptions
+	ordered bool
+}
+
+func (e exporter) structFields(ks *Struct) (*orderedFields, error) {
+	fields := &orderedFields{}
+	for _, field := range ks.schema.fields {
+		v, err := ks.GetField(field)
+		if err != nil {
+			return nil, err
+		}
+		if tf, ok := field.def.t.(*TaggedFields); ok {
+			v, err = e.taggedFields(tf, v)
+		} else {
+			v, err = e.value(v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error exporting field '%s': %v", field.def.name, err)
+		}
+		fields.add(field.def.name, v)
+	}
+	return fields, nil
+}
+
+// taggedFields exports the tagged fields keyed by field name, or by tag when the tag is unknown
+func (e exporter) taggedFields(tf *TaggedFields, v interface{}) (interface{}, error) {
+	objects, err := tf.objects(v)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]int, 0, len(objects))
+	for tag := range objects {
+		tags = append(tags, tag)
+	}
+	sort.Ints(tags)
+
+	fields := &orderedFields{}
+	for _, tag := range tags {
+		name := strconv.Itoa(tag)
+		if field, ok := tf.fields[tag]; ok {
+			name = field.name
+		}
+		obj, err := e.value(objects[tag])
+		if err != nil {
+			return nil, fmt.Errorf("error exporting tagged field %d: %v", tag, err)
+		}
+		fields.add(name, obj)
+	}
+	return e.fields(fields), nil
+}
+
+func (e exporter) value(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case nil:
+		return nil, nil
+	case *Struct:
+		fields, err := e.structFields(value)
+		if err != nil {
+			return nil, err
+		}
+		return e.fields(fields), nil
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, obj := range value {
+			o, err := e.value(obj)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = o
+		}
+		return array, nil
+	case *buffer.ByteBuffer:
+		return e.bytes(value.Bytes()), nil
+	case *RawTaggedField:
+		return e.bytes(value.Data), nil
+	case Uuid:
+		return value.String(), nil
+	default:
+		return value, nil
+	}
+}
+
+func (e exporter) fields(fields *orderedFields) interface{} {
+	if e.ordered {
+		return fields
+	}
+	return fields.toMap()
+}
+
+func (e exporter) bytes(bs []byte) string {
+	if e.opts.BytesEncoding == HexEncoding {
+		return hex.EncodeToString(bs)
+	}
+	return base64.StdEncoding.EncodeToString(bs)
+}
@@ -0,0 +1,86 @@
+package kafkaschema
+
+import (
+	"kafka_schema/schema/buffer"
+	"reflect"
+	"testing"
+)
+
+func newExportSchema(t *testing.T) *Schema {
+	t.Helper()
+	member, err := NewSchema(NewField("id", STRING))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := NewSchema(
+		NewField("name", STRING),
+		NewField("host", NullableString),
+		NewField("data", BYTES),
+		NewField("id", UUID),
+		NewField("members", NewArrayOf(member)),
+		NewTaggedFieldsSection(map[int]*Field{1: NewField("epoch", INT32)}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sch
+}
+
+// newExportStruct returns a group g with a null host, the member m1, the known tagged field
+// epoch and the unknown tag 5
+func newExportStruct(sch *Schema) *Struct {
+	var id Uuid
+	for i := range id {
+		id[i] = byte(i)
+	}
+	member := sch.fields[4].def.t.(*ArrayOf).t.(*Schema)
+	return NewStruct(sch, []interface{}{
+		"g", nil, buffer.Wrap([]byte{0xaa, 0xbb}), id,
+		[]interface{}{NewStruct(member, []interface{}{"m1"})},
+		map[int]interface{}{1: int32(7), 5: &RawTaggedField{Tag: 5, Data: []byte{1, 2}}},
+	})
+}
+
+func TestStructMarshalJSON(t *testing.T) {
+	ks := newExportStruct(newExportSchema(t))
+	tests := []struct {
+		name string
+		opts ExportOptions
+		want string
+	}{
+		{name: "base64", opts: ExportOptions{}, want: `{"name":"g","host":null,"data":"qrs=","id":"AAECAwQFBgcICQoLDA0ODw",` +
+			`"members":[{"id":"m1"}],"_tagged_fields":{"epoch":7,"5":"AQI="}}`},
+		{name: "hex", opts: ExportOptions{BytesEncoding: HexEncoding}, want: `{"name":"g","host":null,"data":"aabb","id":"AAECAwQFBgcICQoLDA0ODw",` +
+			`"members":[{"id":"m1"}],"_tagged_fields":{"epoch":7,"5":"0102"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ks.MarshalJSONWithOptions(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSONWithOptions() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructToMap(t *testing.T) {
+	ks := newExportStruct(newExportSchema(t))
+	got, err := ks.ToMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"name":                  "g",
+		"host":                  nil,
+		"data":                  "qrs=",
+		"id":                    "AAECAwQFBgcICQoLDA0ODw",
+		"members":               []interface{}{map[string]interface{}{"id": "m1"}},
+		TaggedFieldsSectionName: map[string]interface{}{"epoch": int32(7), "5": "AQI="},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToMap() = %v, want %v", got, want)
+	}
+}
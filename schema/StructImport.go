@@ -0,0 +1,210 @@
+package kafkaschema
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"kafka_schema/schema/buffer"
+	"strconv"
+	"strings"
+)
+
+// StructFromJSON parses a JSON object into a Struct of the schema, it is the inverse of
+// MarshalJSON. The value of each field is converted to the type of the field and checked
+// by its Validate, the fields absent from the object get their default value.
+func StructFromJSON(schema *Schema, data []byte) (*Struct, error) {
+	return StructFromJSONWithOptions(schema, data, ExportOptions{})
+}
+
+// StructFromJSONWithOptions is StructFromJSON for a JSON exported with the given options
+func StructFromJSONWithOptions(schema *Schema, data []byte, opts ExportOptions) (*Struct, error) {
+	var raw interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after the top-level object")
+	}
+	return importer{opts: opts}.structValue(schema, raw)
+}
+
+// UnmarshalJSON parses a JSON object with the schema of the struct, which must have been
+// created with a schema, e.g. by NewStruct1
+func (ks *Struct) UnmarshalJSON(data []byte) error {
+	if ks.schema == nil {
+		return fmt.Errorf("cannot unmarshal JSON into a Struct without schema")
+	}
+	parsed, err := StructFromJSON(ks.schema, data)
+	if err != nil {
+		return err
+	}
+	ks.values, ks.set = parsed.values, parsed.set
+	return nil
+}
+
+type importer struct {
+	opts ExportOptions
+}
+
+func (im importer) structValue(schema *Schema, raw interface{}) (*Struct, error) {
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%v is not a JSON object", raw)
+	}
+	ks := NewStruct1(schema)
+	for name, v := range object {
+		field, err := schema.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := im.value(field.def.t, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for field '%s': %v", name, err)
+		}
+		ks.setValue(field, value)
+	}
+	if _, err := schema.Validate(ks); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+func (im importer) value(t Type, raw interface{}) (interface{}, error) {
+	if raw == nil {
+		return t.Validate(nil)
+	}
+	var value interface{}
+	var err error
+	switch tt := t.(type) {
+	case *Schema:
+		return im.structValue(tt, raw)
+	case *ArrayOf:
+		return im.array(tt.t, raw)
+	case *CompactArrayOf:
+		return im.array(tt.t, raw)
+	case *TaggedFields:
+		return im.taggedFields(tt, raw)
+	case *i8:
+		value, err = im.integer(raw, 8, func(v int64) interface{} { return int8(v) })
+	case *i16:
+		value, err = im.integer(raw, 16, func(v int64) interface{} { return int16(v) })
+	case *i32, *varint, *unsignedVarint:
+		value, err = im.integer(raw, 32, func(v int64) interface{} { return int32(v) })
+	case *i64, *varlong:
+		value, err = im.integer(raw, 64, func(v int64) interface{} { return v })
+	case *u16:
+		value, err = im.unsigned(raw, 16, func(v uint64) interface{} { return uint16(v) })
+	case *u32:
+		value, err = im.unsigned(raw, 32, func(v uint64) interface{} { return uint32(v) })
+	case *f64:
+		number, ok := raw.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("%v is not a number", raw)
+		}
+		value, err = number.Float64()
+	case *uuid:
+		str, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%v is not a UUID string", raw)
+		}
+		value, err = ParseUuid(str)
+	case *bytes, *nullableBytes, *compactBytes, *compactNullableBytes:
+		var bs []byte
+		bs, err = im.bytes(raw)
+		value = buffer.NewByteBuffer(bs)
+	default:
+		value = raw
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t.Validate(value)
+}
+
+func (im importer) array(t Type, raw interface{}) (interface{}, error) {
+	array, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%v is not a JSON array", raw)
+	}
+	objs := make([]interface{}, len(array))
+	for i, obj := range array {
+		o, err := im.value(t, obj)
+		if err != nil {
+			return nil, fmt.Errorf("invalid element %d: %v", i, err)
+		}
+		objs[i] = o
+	}
+	return objs, nil
+}
+
+// taggedFields parses the tagged fields keyed by field name, or by tag for the raw
+// tagged fields whose data is encoded like BYTES
+func (im importer) taggedFields(tf *TaggedFields, raw interface{}) (interface{}, error) {
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%v is not a JSON object", raw)
+	}
+	names := make(map[string]int, len(tf.fields))
+	for tag, field := range tf.fields {
+		names[field.name] = tag
+	}
+	objects := make(map[int]interface{}, len(object))
+	for key, v := range object {
+		if tag, ok := names[key]; ok {
+			obj, err := im.value(tf.fields[tag].t, v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for tagged field '%s': %v", key, err)
+			}
+			objects[tag] = obj
+			continue
+		}
+		tag, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("unknown tagged field '%s'", key)
+		}
+		data, err := im.bytes(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for tagged field %d: %v", tag, err)
+		}
+		objects[tag] = &RawTaggedField{Tag: tag, Data: data}
+	}
+	return tf.Validate(objects)
+}
+
+func (im importer) integer(raw interface{}, bitSize int, convert func(int64) interface{}) (interface{}, error) {
+	number, ok := raw.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a number", raw)
+	}
+	v, err := strconv.ParseInt(number.String(), 10, bitSize)
+	if err != nil {
+		return nil, fmt.Errorf("%v is not an integer of %d bits", raw, bitSize)
+	}
+	return convert(v), nil
+}
+
+func (im importer) unsigned(raw interface{}, bitSize int, convert func(uint64) interface{}) (interface{}, error) {
+	number, ok := raw.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a number", raw)
+	}
+	v, err := strconv.ParseUint(number.String(), 10, bitSize)
+	if err != nil {
+		return nil, fmt.Errorf("%v is not an unsigned integer of %d bits", raw, bitSize)
+	}
+	return convert(v), nil
+}
+
+func (im importer) bytes(raw interface{}) ([]byte, error) {
+	str, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("%v is not an encoded bytes string", raw)
+	}
+	if im.opts.BytesEncoding == HexEncoding {
+		return hex.DecodeString(str)
+	}
+	return base64.StdEncoding.DecodeString(str)
+}
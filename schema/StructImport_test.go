@@ -0,0 +1,87 @@
+package kafkaschema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStructFromJSONRoundTrip(t *testing.T) {
+	sch := newExportSchema(t)
+	ks := newExportStruct(sch)
+	for _, opts := range []ExportOptions{{}, {BytesEncoding: HexEncoding}} {
+		data, err := ks.MarshalJSONWithOptions(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := StructFromJSONWithOptions(sch, data, opts)
+		if err != nil {
+			t.Fatalf("StructFromJSONWithOptions(%s) error = %v", data, err)
+		}
+		if !parsed.Equal(ks) {
+			t.Errorf("StructFromJSONWithOptions(%s) = %v, want %v", data, parsed, ks)
+		}
+	}
+}
+
+func TestStructUnmarshalJSON(t *testing.T) {
+	sch := newExportSchema(t)
+	ks := newExportStruct(sch)
+	data, err := json.Marshal(ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := NewStruct1(sch)
+	if err := json.Unmarshal(data, parsed); err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(ks) {
+		t.Errorf("json.Unmarshal(%s) = %v, want %v", data, parsed, ks)
+	}
+	if err := json.Unmarshal(data, &Struct{}); err == nil {
+		t.Error("json.Unmarshal() into a struct without schema succeeded")
+	}
+}
+
+func TestStructFromJSONDefaults(t *testing.T) {
+	sch, err := NewSchema(
+		NewField("name", STRING),
+		NewField1("epoch", INT32, "", int32(-1)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks, err := StructFromJSON(sch, []byte(`{"name":"g"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if epoch, err := Get[int32](ks, "epoch"); err != nil || epoch != -1 {
+		t.Errorf("Get(epoch) = %d, %v, want the default -1", epoch, err)
+	}
+}
+
+func TestStructFromJSONInvalid(t *testing.T) {
+	sch := newExportSchema(t)
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "not an object", data: `[1]`},
+		{name: "unknown field", data: `{"unknown":1}`},
+		{name: "string for a number", data: `{"_tagged_fields":{"epoch":"7"}}`},
+		{name: "integer overflow", data: `{"_tagged_fields":{"epoch":2147483648}}`},
+		{name: "unknown tagged field", data: `{"_tagged_fields":{"time":1}}`},
+		{name: "null string", data: `{"name":null}`},
+		{name: "invalid bytes", data: `{"data":"*"}`},
+		{name: "invalid uuid", data: `{"id":"AAEC"}`},
+		{name: "array for a struct", data: `{"members":[[]]}`},
+		{name: "trailing data", data: `{"name":"g"} {}`},
+		{name: "invalid JSON", data: `{"name":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ks, err := StructFromJSON(sch, []byte(tt.data)); err == nil {
+				t.Errorf("StructFromJSON(%s) = %v, want an error", tt.data, ks)
+			}
+		})
+	}
+}
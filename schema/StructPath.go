@@ -0,0 +1,250 @@
+package kafkaschema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PathError is an error of GetPath or Select naming the segment of the path that failed
+type PathError struct {
+	Path    string
+	Segment string
+	Err     error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("path '%s': segment '%s': %v", e.Path, e.Segment, e.Err)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// wildcard is the index of a [*] selector
+const wildcard = -1
+
+type pathSegment struct {
+	text    string
+	name    string
+	indexes []int
+}
+
+// parsePath splits a path such as "members[2].client_host" or "members[*].member_id" into
+// its segments, a segment is a field name followed by any number of array selectors
+func parsePath(path string) ([]pathSegment, error) {
+	if path == "" {
+		return nil, &PathError{Path: path, Err: fmt.Errorf("empty path")}
+	}
+	texts := strings.Split(path, ".")
+	segments := make([]pathSegment, len(texts))
+	for i, text := range texts {
+		segment := pathSegment{text: text}
+		open := strings.IndexByte(text, '[')
+		if open < 0 {
+			segment.name = text
+		} else {
+			segment.name = text[:open]
+			rest := text[open:]
+			for rest != "" {
+				end := strings.IndexByte(rest, ']')
+				if rest[0] != '[' || end < 0 {
+					return nil, &PathError{Path: path, Segment: text, Err: fmt.Errorf("malformed array selector '%s'", rest)}
+				}
+				selector := rest[1:end]
+				if selector == "*" {
+					segment.indexes = append(segment.indexes, wildcard)
+				} else {
+					index, err := strconv.Atoi(selector)
+					if err != nil || index < 0 {
+						return nil, &PathError{Path: path, Segment: text, Err: fmt.Errorf("invalid array index '%s'", selector)}
+					}
+					segment.indexes = append(segment.indexes, index)
+				}
+				rest = rest[end+1:]
+			}
+		}
+		if segment.name == "" {
+			return nil, &PathError{Path: path, Segment: text, Err: fmt.Errorf("missing field name")}
+		}
+		segments[i] = segment
+	}
+	return segments, nil
+}
+
+// GetPath returns the value at the path, made of field names separated by dots with array
+// indexes, e.g. "members[2].client_host". Wildcards are not allowed, see Select.
+func (ks *Struct) GetPath(path string) (interface{}, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments {
+		for _, index := range segment.indexes {
+			if index == wildcard {
+				return nil, &PathError{Path: path, Segment: segment.text, Err: fmt.Errorf("wildcards are only allowed by Select")}
+			}
+		}
+	}
+	values, err := ks.selectSegments(path, segments)
+	if err != nil {
+		return nil, err
+	}
+	return values[0], nil
+}
+
+// Select returns the values at the path in order, the path can select every element of
+// an array with the [*] wildcard, e.g. "members[*].member_id". A wildcard on a null array
+// selects nothing.
+func (ks *Struct) Select(path string) ([]interface{}, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return ks.selectSegments(path, segments)
+}
+
+func (ks *Struct) selectSegments(path string, segments []pathSegment) ([]interface{}, error) {
+	values := []interface{}{ks}
+	for _, segment := range segments {
+		next := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			selected, err := selectSegment(value, segment)
+			if err != nil {
+				return nil, &PathError{Path: path, Segment: segment.text, Err: err}
+			}
+			next = append(next, selected...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func selectSegment(value interface{}, segment pathSegment) ([]interface{}, error) {
+	s, ok := value.(*Struct)
+	if !ok {
+		if value == nil {
+			return nil, fmt.Errorf("cannot get field '%s' of null", segment.name)
+		}
+		return nil, fmt.Errorf("cannot get field '%s' of %T which is not a struct", segment.name, value)
+	}
+	field, err := s.get(segment.name)
+	if err != nil {
+		return nil, err
+	}
+	values := []interface{}{field}
+	for _, index := range segment.indexes {
+		next := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			array, ok := v.([]interface{})
+			if !ok && v != nil {
+				return nil, fmt.Errorf("%T is not an array", v)
+			}
+			if index == wildcard {
+				next = append(next, array...)
+				continue
+			}
+			if v == nil {
+				return nil, fmt.Errorf("cannot get element %d of a null array", index)
+			}
+			if index >= len(array) {
+				return nil, fmt.Errorf("index %d out of range, the array has %d elements", index, len(array))
+			}
+			next = append(next, array[index])
+		}
+		values = next
+	}
+	return values, nil
+}
+
+// GetPathStruct returns the struct at the path
+func (ks *Struct) GetPathStruct(path string) (*Struct, error) {
+	v, err := ks.GetPath(path)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.(*Struct)
+	if !ok {
+		return nil, &PathError{Path: path, Segment: lastSegment(path), Err: fmt.Errorf("%v is not a struct", v)}
+	}
+	return s, nil
+}
+
+// GetPathString returns the string at the path, a null value is returned as an empty string
+func (ks *Struct) GetPathString(path string) (string, error) {
+	v, err := ks.GetPath(path)
+	if err != nil || v == nil {
+		return "", err
+	}
+	s, err := convertValue[string](path, v)
+	if err != nil {
+		return "", &PathError{Path: path, Segment: lastSegment(path), Err: err}
+	}
+	return s, nil
+}
+
+func (ks *Struct) GetPathInt(path string) (int, error) {
+	v, err := ks.GetPath(path)
+	if err != nil {
+		return 0, err
+	}
+	i, err := convertValue[int32](path, v)
+	if err != nil {
+		return 0, &PathError{Path: path, Segment: lastSegment(path), Err: err}
+	}
+	return int(i), nil
+}
+
+func (ks *Struct) GetPathInt64(path string) (int64, error) {
+	v, err := ks.GetPath(path)
+	if err != nil {
+		return 0, err
+	}
+	i, err := convertValue[int64](path, v)
+	if err != nil {
+		return 0, &PathError{Path: path, Segment: lastSegment(path), Err: err}
+	}
+	return i, nil
+}
+
+// SelectStrings returns the strings at the path, a null value is returned as a nil pointer
+// so that it cannot be mistaken for an empty string
+func (ks *Struct) SelectStrings(path string) ([]*string, error) {
+	values, err := ks.Select(path)
+	if err != nil {
+		return nil, err
+	}
+	strs := make([]*string, len(values))
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		str, err := convertValue[string](path, v)
+		if err != nil {
+			return nil, &PathError{Path: path, Segment: lastSegment(path), Err: err}
+		}
+		strs[i] = &str
+	}
+	return strs, nil
+}
+
+// SelectStructs returns the structs at the path
+func (ks *Struct) SelectStructs(path string) ([]*Struct, error) {
+	values, err := ks.Select(path)
+	if err != nil {
+		return nil, err
+	}
+	structs := make([]*Struct, len(values))
+	for i, v := range values {
+		s, ok := v.(*Struct)
+		if !ok {
+			return nil, &PathError{Path: path, Segment: lastSegment(path), Err: fmt.Errorf("%v is not a struct", v)}
+		}
+		structs[i] = s
+	}
+	return structs, nil
+}
+
+func lastSegment(path string) string {
+	return path[strings.LastIndexByte(path, '.')+1:]
+}
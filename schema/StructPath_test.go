@@ -0,0 +1,143 @@
+package kafkaschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newPathStruct returns a group with the members m1, whose host is h1 and whose topics are
+// t1 and t2, and m2 whose host and topics are null
+func newPathStruct(t *testing.T) *Struct {
+	t.Helper()
+	member, err := NewSchema(
+		NewField("id", STRING),
+		NewField("host", NullableString),
+		NewField("topics", NewArrayOf1(STRING, true)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	group, err := NewSchema(
+		NewField("name", STRING),
+		NewField("members", NewArrayOf(member)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m1 := NewStruct(member, []interface{}{"m1", "h1", []interface{}{"t1", "t2"}})
+	m2 := NewStruct(member, []interface{}{"m2", nil, nil})
+	return NewStruct(group, []interface{}{"g", []interface{}{m1, m2}})
+}
+
+func TestStructGetPath(t *testing.T) {
+	ks := newPathStruct(t)
+	tests := []struct {
+		path    string
+		want    interface{}
+		wantErr bool
+	}{
+		{path: "name", want: "g"},
+		{path: "members[0].id", want: "m1"},
+		{path: "members[0].topics[1]", want: "t2"},
+		{path: "members[1].host", want: nil},
+		{path: "members[2].id", wantErr: true},
+		{path: "members[1].topics[0]", wantErr: true},
+		{path: "members[*].id", wantErr: true},
+		{path: "name.id", wantErr: true},
+		{path: "unknown", wantErr: true},
+		{path: "members[x]", wantErr: true},
+		{path: "members[0", wantErr: true},
+		{path: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ks.GetPath(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("GetPath() = %v, want an error", got)
+				} else if _, ok := err.(*PathError); !ok {
+					t.Errorf("GetPath() error = %T, want a *PathError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("GetPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructSelect(t *testing.T) {
+	ks := newPathStruct(t)
+	tests := []struct {
+		path string
+		want []interface{}
+	}{
+		{path: "members[*].id", want: []interface{}{"m1", "m2"}},
+		{path: "members[*].host", want: []interface{}{"h1", nil}},
+		{path: "members[*].topics[*]", want: []interface{}{"t1", "t2"}},
+		{path: "members[1].topics[*]", want: []interface{}{}},
+		{path: "members[0].topics", want: []interface{}{[]interface{}{"t1", "t2"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ks.Select(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructSelectStrings(t *testing.T) {
+	ks := newPathStruct(t)
+	hosts, err := ks.SelectStrings("members[*].host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 2 || hosts[0] == nil || *hosts[0] != "h1" || hosts[1] != nil {
+		t.Errorf("SelectStrings() = %v, want h1 and null", hosts)
+	}
+	if _, err := ks.SelectStrings("members[*].topics"); err == nil {
+		t.Error("SelectStrings() of arrays succeeded")
+	}
+}
+
+func TestStructTypedPaths(t *testing.T) {
+	ks := newPathStruct(t)
+	if s, err := ks.GetPathString("members[0].host"); err != nil || s != "h1" {
+		t.Errorf("GetPathString(members[0].host) = %q, %v, want h1", s, err)
+	}
+	if s, err := ks.GetPathString("members[1].host"); err != nil || s != "" {
+		t.Errorf("GetPathString(members[1].host) = %q, %v, want an empty string", s, err)
+	}
+	if _, err := ks.GetPathString("members"); err == nil {
+		t.Error("GetPathString() of an array succeeded")
+	}
+	member, err := ks.GetPathStruct("members[1]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := Get[string](member, "id"); err != nil || id != "m2" {
+		t.Errorf("GetPathStruct(members[1]) = %v, want the member m2", member)
+	}
+	if _, err := ks.GetPathStruct("name"); err == nil {
+		t.Error("GetPathStruct() of a string succeeded")
+	}
+	if _, err := ks.GetPathInt("name"); err == nil {
+		t.Error("GetPathInt() of a string succeeded")
+	}
+	members, err := ks.SelectStructs("members[*]")
+	if err != nil || len(members) != 2 {
+		t.Errorf("SelectStructs(members[*]) = %v, %v, want two members", members, err)
+	}
+	if _, err := ks.SelectStructs("members[*].id"); err == nil {
+		t.Error("SelectStructs() of strings succeeded")
+	}
+}
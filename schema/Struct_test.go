@@ -0,0 +1,140 @@
+package kafkaschema
+
+import (
+	"encoding/hex"
+	"kafka_schema/schema/buffer"
+	"sync"
+	"testing"
+)
+
+func newDefaultsSchema(t *testing.T) *Schema {
+	t.Helper()
+	sch, err := NewSchema(
+		NewField1("n", NullableString, "", "dflt"),
+		NewField1("count", INT32, "", int32(7)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sch
+}
+
+func TestStructNullIsNotDefault(t *testing.T) {
+	sch := newDefaultsSchema(t)
+	data, _ := hex.DecodeString("ffff" + "00000001")
+
+	read, err := sch.Read(buffer.Wrap(data), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := read.(*Struct)
+	if v, err := GetOptional[string](ks, "n"); err != nil || v != nil {
+		t.Errorf("GetOptional(n) of a null read = %v, %v, want nil", v, err)
+	}
+	buf, err := Serialize(sch, ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(buf.Bytes()); got != hex.EncodeToString(data) {
+		t.Errorf("Serialize() = %s, want %x", got, data)
+	}
+	if v, err := GetOptional[string](ks.Clone(), "n"); err != nil || v != nil {
+		t.Errorf("GetOptional(n) of the clone = %v, %v, want nil", v, err)
+	}
+}
+
+func TestStructSetAndUnset(t *testing.T) {
+	sch := newDefaultsSchema(t)
+	tests := []struct {
+		name   string
+		update func(ks *Struct) error
+		want   string
+	}{
+		{name: "unset fields read their default", update: func(ks *Struct) error { return nil }, want: "000464666c74" + "00000007"},
+		{name: "set to null", update: func(ks *Struct) error { return ks.Set("n", nil) }, want: "ffff" + "00000007"},
+		{name: "set to a value", update: func(ks *Struct) error { return ks.Set("n", "a") }, want: "000161" + "00000007"},
+		{name: "unset after null", update: func(ks *Struct) error {
+			if err := ks.Set("n", nil); err != nil {
+				return err
+			}
+			return ks.Unset("n")
+		}, want: "000464666c74" + "00000007"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks := NewStruct(sch, make([]interface{}, 2))
+			if err := tt.update(ks); err != nil {
+				t.Fatal(err)
+			}
+			buf, err := Serialize(sch, ks)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(buf.Bytes()); got != tt.want {
+				t.Errorf("Serialize() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructSetNullNotNullable(t *testing.T) {
+	ks := NewStruct1(newDefaultsSchema(t))
+	if err := ks.Set("count", nil); err == nil {
+		t.Error("Set(count, nil) of a field which is not nullable succeeded")
+	}
+	if !ks.IsSet("count") {
+		t.Error("IsSet(count) = false after a failed Set")
+	}
+}
+
+func TestStructConcurrentReads(t *testing.T) {
+	sch, err := NewSchema(NewField("id", INT32), NewTaggedFieldsSection(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		ks   *Struct
+	}{
+		{name: "NewStruct", ks: NewStruct(sch, []interface{}{int32(1), nil})},
+		{name: "NewStruct1", ks: NewStruct1(sch)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			hashes := make([]uint64, 4)
+			for i := range hashes {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					hashes[i] = tt.ks.Hash()
+					tt.ks.Equal(tt.ks.Clone())
+				}(i)
+			}
+			wg.Wait()
+			for i, h := range hashes {
+				if h != hashes[0] {
+					t.Errorf("Hash() %d = %d, want %d", i, h, hashes[0])
+				}
+			}
+		})
+	}
+}
+
+func TestStructUnsetTaggedFields(t *testing.T) {
+	sch, err := NewSchema(NewField("id", INT32), NewTaggedFieldsSection(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := NewStruct1(sch)
+	if err := ks.Unset(TaggedFieldsSectionName); err != nil {
+		t.Fatal(err)
+	}
+	tagged, err := Get[map[int]interface{}](ks, TaggedFieldsSectionName)
+	if err != nil || tagged == nil || len(tagged) != 0 {
+		t.Errorf("Get(%s) of an unset section = %v, %v, want an empty map", TaggedFieldsSectionName, tagged, err)
+	}
+	if ks.values[1] != nil {
+		t.Errorf("Get(%s) stored %v in the unset section", TaggedFieldsSectionName, ks.values[1])
+	}
+}
@@ -0,0 +1,226 @@
+package kafkaschema
+
+import (
+	"fmt"
+	"kafka_schema/schema/buffer"
+	"sort"
+)
+
+// TaggedFieldsSectionName is the name of the field holding the tagged fields of a flexible schema
+const TaggedFieldsSectionName = "_tagged_fields"
+
+// RawTaggedField is a tagged field whose tag is unknown to the schema, its data is kept as is
+// so that it is written back unchanged
+type RawTaggedField struct {
+	Tag  int
+	Data []byte
+}
+
+// TaggedFields is the tagged field buffer of KIP-482. Its value is a map[int]interface{}
+// from tag to value, tags without a declared field are mapped to a *RawTaggedField.
+type TaggedFields struct {
+	fields map[int]*Field
+}
+
+func NewTaggedFields(fields map[int]*Field) *TaggedFields {
+	if fields == nil {
+		fields = make(map[int]*Field)
+	}
+	return &TaggedFields{fields: fields}
+}
+
+// NewTaggedFieldsSection returns the trailing field of a flexible schema, tags is the
+// set of tagged fields known by the schema and can be nil. Its default is no tagged
+// fields, every Struct of the schema gets its own map when it is created.
+func NewTaggedFieldsSection(tags map[int]*Field) *Field {
+	return newField(TaggedFieldsSectionName, NewTaggedFields(tags), "The tagged fields", true, nil)
+}
+
+// Tags returns the tags known by the section in ascending order
+func (tf TaggedFields) Tags() []int {
+	tags := make([]int, 0, len(tf.fields))
+	for tag := range tf.fields {
+		tags = append(tags, tag)
+	}
+	sort.Ints(tags)
+	return tags
+}
+
+// Field returns the field declared for the tag
+func (tf TaggedFields) Field(tag int) (*Field, bool) {
+	field, ok := tf.fields[tag]
+	return field, ok
+}
+
+func (tf TaggedFields) Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error) {
+	opts = opts.Begin()
+	numTaggedFields, err := buffer.GetUnsignedVarint()
+	if err != nil {
+		return nil, fmt.Errorf("tagged fields count read faild: %w", err)
+	}
+	if numTaggedFields < 0 {
+		return nil, fmt.Errorf("%w: tagged fields count %d", ErrNegativeLength, numTaggedFields)
+	}
+	if err := opts.checkArrayLength(int(numTaggedFields)); err != nil {
+		return nil, err
+	}
+	if int(numTaggedFields) > buffer.Remaining() {
+		return nil, fmt.Errorf("%w: reading %d tagged fields, only %d bytes available", ErrBufferUnderflow, numTaggedFields, buffer.Remaining())
+	}
+	if err := opts.allocate(int(numTaggedFields) * interfaceSize); err != nil {
+		return nil, err
+	}
+	objects := make(map[int]interface{}, numTaggedFields)
+	prevTag := -1
+	for i := 0; i < int(numTaggedFields); i++ {
+		t, err := buffer.GetUnsignedVarint()
+		if err != nil {
+			return nil, fmt.Errorf("tag read faild: %w", err)
+		}
+		tag := int(t)
+		if tag <= prevTag {
+			return nil, fmt.Errorf("invalid or out-of-order tag %d", tag)
+		}
+		prevTag = tag
+		size, err := buffer.GetUnsignedVarint()
+		if err != nil {
+			return nil, fmt.Errorf("tagged field size read faild: %w", err)
+		}
+		if size < 0 || int(size) > buffer.Remaining() {
+			return nil, fmt.Errorf("%w: reading tagged field %d of size %d, only %d bytes available", ErrBufferUnderflow, tag, size, buffer.Remaining())
+		}
+		slice := buffer.Slice()
+		_ = slice.SetLimit(int(size))
+		_ = buffer.SetPosition(buffer.GetPosition() + int(size))
+		if field, ok := tf.fields[tag]; ok {
+			obj, err := field.t.Read(slice, opts)
+			if err != nil {
+				return nil, LocateDecodeError(err, field.name, slice.AbsolutePosition())
+			}
+			if slice.Remaining() != 0 {
+				return nil, fmt.Errorf("tagged field '%s' has %d unread bytes", field.name, slice.Remaining())
+			}
+			objects[tag] = obj
+		} else {
+			if err := opts.allocate(int(size)); err != nil {
+				return nil, err
+			}
+			objects[tag] = &RawTaggedField{Tag: tag, Data: slice.Bytes()}
+		}
+	}
+	return objects, nil
+}
+
+func (tf TaggedFields) SizeOf(o interface{}) (int, error) {
+	objects, err := tf.objects(o)
+	if err != nil {
+		return 0, err
+	}
+	size := buffer.SizeOfUnsignedVarint(int32(len(objects)))
+	for tag, obj := range objects {
+		fieldSize, err := tf.sizeOfField(tag, obj)
+		if err != nil {
+			return 0, err
+		}
+		size += buffer.SizeOfUnsignedVarint(int32(tag)) + buffer.SizeOfUnsignedVarint(int32(fieldSize)) + fieldSize
+	}
+	return size, nil
+}
+
+func (tf TaggedFields) Write(buffer *buffer.ByteBuffer, o interface{}) error {
+	objects, err := tf.objects(o)
+	if err != nil {
+		return err
+	}
+	tags := make([]int, 0, len(objects))
+	for tag := range objects {
+		tags = append(tags, tag)
+	}
+	sort.Ints(tags)
+
+	if err = buffer.PutUnsignedVarint(int32(len(tags))); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		obj := objects[tag]
+		fieldSize, err := tf.sizeOfField(tag, obj)
+		if err != nil {
+			return err
+		}
+		if err = buffer.PutUnsignedVarint(int32(tag)); err != nil {
+			return err
+		}
+		if err = buffer.PutUnsignedVarint(int32(fieldSize)); err != nil {
+			return err
+		}
+		if raw, ok := obj.(*RawTaggedField); ok {
+			err = buffer.PutBytes(raw.Data)
+		} else {
+			err = tf.fields[tag].t.Write(buffer, obj)
+		}
+		if err != nil {
+			return fmt.Errorf("error writing tagged field %d: %v", tag, err)
+		}
+	}
+	return nil
+}
+
+func (tf TaggedFields) sizeOfField(tag int, obj interface{}) (int, error) {
+	if raw, ok := obj.(*RawTaggedField); ok {
+		return len(raw.Data), nil
+	}
+	field, ok := tf.fields[tag]
+	if !ok {
+		return 0, fmt.Errorf("tag %d is unknown and its value is not a RawTaggedField", tag)
+	}
+	return field.t.SizeOf(obj)
+}
+
+func (tf TaggedFields) objects(o interface{}) (map[int]interface{}, error) {
+	if o == nil {
+		return map[int]interface{}{}, nil
+	}
+	objects, ok := o.(map[int]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%v is not a tagged fields map", o)
+	}
+	return objects, nil
+}
+
+func (tf TaggedFields) String() string {
+	return "TAGGED_FIELDS"
+}
+
+func (tf TaggedFields) isNullable() bool {
+	return false
+}
+
+func (tf TaggedFields) Validate(o interface{}) (interface{}, error) {
+	objects, err := tf.objects(o)
+	if err != nil {
+		return nil, err
+	}
+	for tag, obj := range objects {
+		if tag < 0 {
+			return nil, fmt.Errorf("tag %d cannot be negative", tag)
+		}
+		if raw, ok := obj.(*RawTaggedField); ok {
+			if raw.Tag != tag {
+				return nil, fmt.Errorf("raw tagged field with tag %d is stored under tag %d", raw.Tag, tag)
+			}
+			continue
+		}
+		field, ok := tf.fields[tag]
+		if !ok {
+			return nil, fmt.Errorf("tag %d is unknown and its value is not a RawTaggedField", tag)
+		}
+		if _, err = field.t.Validate(obj); err != nil {
+			return nil, fmt.Errorf("invalid value for tagged field '%s': %v", field.name, err)
+		}
+	}
+	return objects, nil
+}
+
+func (tf TaggedFields) TypeName() string {
+	return "TAGGED_FIELDS"
+}
@@ -0,0 +1,123 @@
+package kafkaschema
+
+import (
+	"encoding/hex"
+	"kafka_schema/schema/buffer"
+	"testing"
+)
+
+func TestCompactRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		t     Type
+		value interface{}
+		want  string
+	}{
+		{name: "COMPACT_STRING", t: CompactString, value: "ab", want: "036162"},
+		{name: "empty COMPACT_STRING", t: CompactString, value: "", want: "01"},
+		{name: "COMPACT_NULLABLE_STRING", t: CompactNullableString, value: "ab", want: "036162"},
+		{name: "null COMPACT_NULLABLE_STRING", t: CompactNullableString, value: nil, want: "00"},
+		{name: "COMPACT_BYTES", t: CompactBytes, value: buffer.Wrap([]byte{1, 2}), want: "030102"},
+		{name: "null COMPACT_NULLABLE_BYTES", t: CompactNullableBytes, value: nil, want: "00"},
+		{name: "COMPACT_ARRAY", t: NewCompactArrayOf(INT32), value: []interface{}{int32(1)}, want: "0200000001"},
+		{name: "empty COMPACT_ARRAY", t: NewCompactArrayOf(CompactString), value: []interface{}{}, want: "01"},
+		{name: "null COMPACT_ARRAY", t: NewCompactArrayOf1(INT32, true), value: nil, want: "00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := Serialize(tt.t, tt.value)
+			if err != nil {
+				t.Fatalf("Serialize() error = %v", err)
+			}
+			if got := hex.EncodeToString(buf.Bytes()); got != tt.want {
+				t.Errorf("Serialize() = %s, want %s", got, tt.want)
+			}
+			read, err := tt.t.Read(buf, nil)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !valueEqual(read, tt.value) {
+				t.Errorf("Read() = %v, want %v", read, tt.value)
+			}
+		})
+	}
+}
+
+func newTaggedSchema(t *testing.T) *Schema {
+	t.Helper()
+	sch, err := NewSchema(
+		NewField("name", CompactString),
+		NewTaggedFieldsSection(map[int]*Field{0: NewField("extra", INT32)}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sch
+}
+
+func TestTaggedFieldsRoundTrip(t *testing.T) {
+	sch := newTaggedSchema(t)
+	// name "a", two tagged fields: tag 0 of 4 bytes and the unknown tag 5 of 2 bytes
+	data, _ := hex.DecodeString("0261" + "02" + "0004" + "0000002a" + "0502" + "beef")
+
+	read, err := sch.Read(buffer.Wrap(data), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := read.(*Struct)
+	if v, ok := ks.GetTaggedField(0); !ok || v != int32(42) {
+		t.Errorf("GetTaggedField(0) = %v, %v, want 42", v, ok)
+	}
+	unknown := ks.UnknownTaggedFields()
+	if len(unknown) != 1 || unknown[0].Tag != 5 || hex.EncodeToString(unknown[0].Data) != "beef" {
+		t.Errorf("UnknownTaggedFields() = %v, want tag 5 with beef", unknown)
+	}
+
+	buf, err := Serialize(sch, ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(buf.Bytes()) != hex.EncodeToString(data) {
+		t.Errorf("Serialize() = %x, want %x", buf.Bytes(), data)
+	}
+}
+
+func TestTaggedFieldsReadErrors(t *testing.T) {
+	sch := newTaggedSchema(t)
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "out of order tags", data: "0261" + "02" + "0501ff" + "0004" + "0000002a"},
+		{name: "duplicate tags", data: "0261" + "02" + "0501ff" + "0501ff"},
+		{name: "size past the end", data: "0261" + "01" + "0509ff"},
+		{name: "unread bytes of a known tag", data: "0261" + "01" + "0005" + "0000002aff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := hex.DecodeString(tt.data)
+			if _, err := sch.Read(buffer.Wrap(data), nil); err == nil {
+				t.Error("Read() succeeded")
+			}
+		})
+	}
+}
+
+func TestTaggedFieldsNotShared(t *testing.T) {
+	sch := newTaggedSchema(t)
+	first := NewStruct(sch, []interface{}{"a", nil})
+	second := NewStruct(sch, []interface{}{"b", nil})
+
+	section, err := Get[map[int]interface{}](first, TaggedFieldsSectionName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	section[0] = int32(1)
+
+	if v, ok := first.GetTaggedField(0); !ok || v != int32(1) {
+		t.Errorf("GetTaggedField(0) of the first struct = %v, %v, want 1", v, ok)
+	}
+	if v, ok := second.GetTaggedField(0); ok {
+		t.Errorf("GetTaggedField(0) of the second struct = %v, want none", v)
+	}
+}
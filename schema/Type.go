@@ -1,13 +1,17 @@
 package kafkaschema
 
 import (
+	"fmt"
 	"kafka_schema/schema/buffer"
 )
 
 type Type interface {
 
-	// Read the typed object from the buffer
-	Read(buffer *buffer.ByteBuffer) (interface{}, error)
+	// Read the typed object from the buffer within the limits of the options, which can be nil
+	Read(buffer *buffer.ByteBuffer, opts *DecodeOptions) (interface{}, error)
+
+	// Write the typed object to the buffer
+	Write(buffer *buffer.ByteBuffer, o interface{}) error
 
 	// Validate the object. If succeeded return its typed object.
 	Validate(o interface{}) (interface{}, error)
@@ -21,3 +25,26 @@ type Type interface {
 	// String Return the string of type
 	String() string
 }
+
+// Serialize writes o with the type t into a new buffer of exactly SizeOf(o) bytes,
+// the returned buffer is positioned at its beginning and ready to be read.
+func Serialize(t Type, o interface{}) (*buffer.ByteBuffer, error) {
+	size, err := t.SizeOf(o)
+	if err != nil {
+		return nil, err
+	}
+	buf := buffer.Allocate(size)
+	if err = t.Write(buf, o); err != nil {
+		return nil, err
+	}
+	if buf.Remaining() != 0 {
+		return nil, fmt.Errorf("serialized size %d does not match computed size %d", size-buf.Remaining(), size)
+	}
+	err = buf.SetPosition(0)
+	return buf, err
+}
+
+// IsNullable returns true if the type supports null values
+func IsNullable(t Type) bool {
+	return t.isNullable()
+}
@@ -0,0 +1,29 @@
+package kafkaschema
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// Uuid is a 128 bits identifier, represented as a url-safe base64 string like Kafka does
+type Uuid [16]byte
+
+// ZeroUuid is the uuid with all bits unset, it stands for an unset identifier
+var ZeroUuid = Uuid{}
+
+func ParseUuid(str string) (Uuid, error) {
+	var uuid Uuid
+	bs, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return uuid, fmt.Errorf("invalid uuid '%s': %v", str, err)
+	}
+	if len(bs) != len(uuid) {
+		return uuid, fmt.Errorf("invalid uuid '%s': decoded to %d bytes instead of %d", str, len(bs), len(uuid))
+	}
+	copy(uuid[:], bs)
+	return uuid, nil
+}
+
+func (u Uuid) String() string {
+	return base64.RawURLEncoding.EncodeToString(u[:])
+}
@@ -0,0 +1,276 @@
+package kafkaschema
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// VersionedField is a field of a VersionedSchema, it exists in a range of versions, is
+// nullable in a range of versions and is a tagged field in a range of flexible versions.
+// Its type is either a Type, a struct of a VersionedSchema or an array of such structs.
+type VersionedField struct {
+	name             string
+	t                Type
+	element          *VersionedSchema
+	array            bool
+	docString        string
+	hasDefaultValue  bool
+	defaultValue     interface{}
+	versions         Versions
+	nullableVersions Versions
+	tag              int
+	taggedVersions   Versions
+}
+
+// NewVersionedField returns a field of type t existing in the given versions, t is the
+// non nullable type and is made nullable in the nullable versions of the field
+func NewVersionedField(name string, t Type, versions Versions) *VersionedField {
+	return &VersionedField{name: name, t: t, versions: versions, nullableVersions: NoVersions, taggedVersions: NoVersions}
+}
+
+// NewVersionedArrayField returns an array field whose elements are the structs of element
+// at the version of the schema the field belongs to
+func NewVersionedArrayField(name string, element *VersionedSchema, versions Versions) *VersionedField {
+	return &VersionedField{name: name, element: element, array: true, versions: versions, nullableVersions: NoVersions, taggedVersions: NoVersions}
+}
+
+// NewVersionedStructField returns a field holding a struct of element at the version of the
+// schema the field belongs to
+func NewVersionedStructField(name string, element *VersionedSchema, versions Versions) *VersionedField {
+	return &VersionedField{name: name, element: element, versions: versions, nullableVersions: NoVersions, taggedVersions: NoVersions}
+}
+
+func (vf *VersionedField) WithDoc(docString string) *VersionedField {
+	vf.docString = docString
+	return vf
+}
+
+func (vf *VersionedField) WithDefault(defaultValue interface{}) *VersionedField {
+	vf.hasDefaultValue = true
+	vf.defaultValue = defaultValue
+	return vf
+}
+
+func (vf *VersionedField) WithNullableVersions(versions Versions) *VersionedField {
+	vf.nullableVersions = versions
+	return vf
+}
+
+// WithTag makes the field the tagged field tag of the tagged fields section in the tagged
+// versions, which must be flexible versions
+func (vf *VersionedField) WithTag(tag int, taggedVersions Versions) *VersionedField {
+	vf.tag = tag
+	vf.taggedVersions = taggedVersions
+	return vf
+}
+
+func (vf *VersionedField) Name() string {
+	return vf.name
+}
+
+func (vf *VersionedField) Versions() Versions {
+	return vf.versions
+}
+
+func (vf *VersionedField) NullableVersions() Versions {
+	return vf.nullableVersions
+}
+
+// Tag returns the tag of the field and its tagged versions, which are empty when the field
+// is never tagged
+func (vf *VersionedField) Tag() (int, Versions) {
+	return vf.tag, vf.taggedVersions
+}
+
+// Element returns the schema of the structs of a struct or array field, nil for the other fields
+func (vf *VersionedField) Element() *VersionedSchema {
+	return vf.element
+}
+
+// field returns the field at the version, flexible versions use the compact types
+func (vf *VersionedField) field(version int16, flexible bool) (*Field, error) {
+	var t Type
+	var err error
+	if vf.element != nil {
+		element, err := vf.element.Schema(version)
+		if err != nil {
+			return nil, err
+		}
+		t = element
+		if vf.array {
+			t = NewArrayOf(element)
+		}
+	} else {
+		t = vf.t
+	}
+	if vf.nullableVersions.Contains(version) {
+		if t, err = nullableType(t); err != nil {
+			return nil, err
+		}
+	}
+	if flexible {
+		if t, err = compactType(t); err != nil {
+			return nil, err
+		}
+	}
+	return newField(vf.name, t, vf.docString, vf.hasDefaultValue, vf.defaultValue), nil
+}
+
+// VersionedSchema is a message defined once for all its versions, the Schema of a version
+// is made of the fields existing in the version in the order of the definition
+type VersionedSchema struct {
+	name             string
+	versions         Versions
+	flexibleVersions Versions
+	fields           []*VersionedField
+	schemas          map[int16]*Schema
+}
+
+// NewVersionedSchema derives the schema of every version of the message, the valid versions
+// must have a highest version. The schemas of the flexible versions use the compact types and
+// end with a tagged fields section holding the fields tagged in the version.
+func NewVersionedSchema(name string, versions, flexibleVersions Versions, fields ...*VersionedField) (*VersionedSchema, error) {
+	if versions.IsEmpty() {
+		return nil, fmt.Errorf("%s has no valid versions", name)
+	}
+	if versions.Highest == math.MaxInt16 {
+		return nil, fmt.Errorf("%s valid versions %s have no highest version", name, versions)
+	}
+	vs := &VersionedSchema{
+		name:             name,
+		versions:         versions,
+		flexibleVersions: flexibleVersions,
+		fields:           fields,
+		schemas:          make(map[int16]*Schema),
+	}
+	for version := int(versions.Lowest); version <= int(versions.Highest); version++ {
+		schema, err := vs.derive(int16(version))
+		if err != nil {
+			return nil, fmt.Errorf("%s version %d: %v", name, version, err)
+		}
+		vs.schemas[int16(version)] = schema
+	}
+	return vs, nil
+}
+
+func (vs *VersionedSchema) derive(version int16) (*Schema, error) {
+	flexible := vs.flexibleVersions.Contains(version)
+	fs := make([]*Field, 0, len(vs.fields)+1)
+	tags := make(map[int]*Field)
+	for _, vf := range vs.fields {
+		if !vf.versions.Contains(version) {
+			continue
+		}
+		f, err := vf.field(version, flexible)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", vf.name, err)
+		}
+		if !vf.taggedVersions.Contains(version) {
+			fs = append(fs, f)
+		} else if !flexible {
+			return nil, fmt.Errorf("field %s is tagged in version %d which is not flexible", vf.name, version)
+		} else if _, ok := tags[vf.tag]; ok {
+			return nil, fmt.Errorf("field %s: tag %d is used twice", vf.name, vf.tag)
+		} else {
+			tags[vf.tag] = f
+		}
+	}
+	if flexible {
+		fs = append(fs, NewTaggedFieldsSection(tags))
+	}
+	return NewSchema(fs...)
+}
+
+func (vs *VersionedSchema) Name() string {
+	return vs.name
+}
+
+func (vs *VersionedSchema) Versions() Versions {
+	return vs.versions
+}
+
+func (vs *VersionedSchema) FlexibleVersions() Versions {
+	return vs.flexibleVersions
+}
+
+// Fields returns the definitions of the fields of every version
+func (vs *VersionedSchema) Fields() []*VersionedField {
+	fields := make([]*VersionedField, len(vs.fields))
+	copy(fields, vs.fields)
+	return fields
+}
+
+// Schema returns the schema of the version
+func (vs *VersionedSchema) Schema(version int16) (*Schema, error) {
+	schema, ok := vs.schemas[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no version %d, valid versions are %s", ErrUnknownVersion, vs.name, version, vs.versions)
+	}
+	return schema, nil
+}
+
+// Schemas returns the schema of every version by version
+func (vs *VersionedSchema) Schemas() map[int]*Schema {
+	schemas := make(map[int]*Schema, len(vs.schemas))
+	for version, schema := range vs.schemas {
+		schemas[int(version)] = schema
+	}
+	return schemas
+}
+
+// SchemaVersions returns the versions of the message in ascending order
+func (vs *VersionedSchema) SchemaVersions() []int16 {
+	versions := make([]int16, 0, len(vs.schemas))
+	for version := range vs.schemas {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] < versions[j]
+	})
+	return versions
+}
+
+func nullableType(t Type) (Type, error) {
+	switch tt := t.(type) {
+	case *s:
+		return NullableString, nil
+	case *bytes:
+		return NullableBytes, nil
+	case *compactString:
+		return CompactNullableString, nil
+	case *compactBytes:
+		return CompactNullableBytes, nil
+	case *ArrayOf:
+		return NewArrayOf1(tt.t, true), nil
+	case *CompactArrayOf:
+		return NewCompactArrayOf1(tt.t, true), nil
+	}
+	if t.isNullable() {
+		return t, nil
+	}
+	return nil, fmt.Errorf("type %s cannot be nullable", TypeName(t))
+}
+
+// compactType returns the type of a flexible version for the types having a compact
+// encoding, the other types are returned as is
+func compactType(t Type) (Type, error) {
+	switch tt := t.(type) {
+	case *s:
+		return CompactString, nil
+	case *nullableString:
+		return CompactNullableString, nil
+	case *bytes:
+		return CompactBytes, nil
+	case *nullableBytes:
+		return CompactNullableBytes, nil
+	case *ArrayOf:
+		element, err := compactType(tt.t)
+		if err != nil {
+			return nil, err
+		}
+		return NewCompactArrayOf1(element, tt.nullable), nil
+	default:
+		return t, nil
+	}
+}
@@ -0,0 +1,111 @@
+package kafkaschema
+
+import (
+	"testing"
+)
+
+func TestNewVersionedSchema(t *testing.T) {
+	vs, err := NewVersionedSchema("Value", VersionRange(0, 2), VersionsFrom(2),
+		NewVersionedField("name", STRING, VersionsFrom(0)),
+		NewVersionedField("epoch", INT32, VersionsFrom(1)),
+		NewVersionedField("note", STRING, VersionsFrom(0)).WithNullableVersions(VersionsFrom(1)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int16]string{
+		0: "{name:STRING,note:STRING}",
+		1: "{name:STRING,epoch:INT32,note:NULLABLE_STRING}",
+		2: "{name:COMPACT_STRING,epoch:INT32,note:COMPACT_NULLABLE_STRING,_tagged_fields:TAGGED_FIELDS}",
+	}
+	for version, s := range want {
+		schema, err := vs.Schema(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := schema.String(); got != s {
+			t.Errorf("Schema(%d) = %s, want %s", version, got, s)
+		}
+	}
+	if _, err := vs.Schema(3); err == nil {
+		t.Error("Schema(3) succeeded")
+	}
+}
+
+func TestNewVersionedSchemaInvalidVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions Versions
+	}{
+		{name: "no versions", versions: NoVersions},
+		{name: "all versions", versions: AllVersions},
+		{name: "open-ended versions", versions: VersionsFrom(3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewVersionedSchema("Value", tt.versions, NoVersions, NewVersionedField("name", STRING, VersionsFrom(0))); err == nil {
+				t.Errorf("NewVersionedSchema(%s) succeeded", tt.versions)
+			}
+		})
+	}
+}
+
+func TestNewVersionedSchemaStructsAndTags(t *testing.T) {
+	member, err := NewVersionedSchema("Member", VersionRange(0, 1), VersionsFrom(1),
+		NewVersionedField("id", STRING, VersionsFrom(0)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs, err := NewVersionedSchema("Value", VersionRange(0, 1), VersionsFrom(1),
+		NewVersionedStructField("leader", member, VersionsFrom(0)),
+		NewVersionedArrayField("members", member, VersionsFrom(0)),
+		NewVersionedField("epoch", INT32, VersionsFrom(1)).WithTag(3, VersionsFrom(1)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int16]string{
+		0: "{leader:{id:STRING},members:ARRAY({id:STRING})}",
+		1: "{leader:{id:COMPACT_STRING,_tagged_fields:TAGGED_FIELDS},members:COMPACT_ARRAY({id:COMPACT_STRING,_tagged_fields:TAGGED_FIELDS}),_tagged_fields:TAGGED_FIELDS}",
+	}
+	for version, s := range want {
+		schema, err := vs.Schema(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := schema.String(); got != s {
+			t.Errorf("Schema(%d) = %s, want %s", version, got, s)
+		}
+	}
+	schema, _ := vs.Schema(1)
+	section, err := schema.Get(TaggedFieldsSectionName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if field, ok := section.def.t.(*TaggedFields).Field(3); !ok || field.Name() != "epoch" {
+		t.Errorf("tag 3 of version 1 = %v, want the field epoch", field)
+	}
+}
+
+func TestNewVersionedSchemaInvalidTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []*VersionedField
+	}{
+		{name: "tagged in a version which is not flexible", fields: []*VersionedField{
+			NewVersionedField("epoch", INT32, VersionsFrom(0)).WithTag(0, VersionsFrom(0)),
+		}},
+		{name: "tag used twice", fields: []*VersionedField{
+			NewVersionedField("epoch", INT32, VersionsFrom(1)).WithTag(0, VersionsFrom(1)),
+			NewVersionedField("time", INT64, VersionsFrom(1)).WithTag(0, VersionsFrom(1)),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewVersionedSchema("Value", VersionRange(0, 1), VersionsFrom(1), tt.fields...); err == nil {
+				t.Error("NewVersionedSchema() succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package kafkaschema
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Versions is an inclusive range of versions as written in the message specifications:
+// "none", "3", "1-3" or "2+"
+type Versions struct {
+	Lowest  int16
+	Highest int16
+}
+
+var (
+	NoVersions  = Versions{Lowest: 0, Highest: -1}
+	AllVersions = Versions{Lowest: 0, Highest: math.MaxInt16}
+)
+
+// VersionRange returns the versions from lowest to highest, "lowest-highest"
+func VersionRange(lowest, highest int16) Versions {
+	return Versions{Lowest: lowest, Highest: highest}
+}
+
+// VersionsFrom returns the versions from lowest on, "lowest+"
+func VersionsFrom(lowest int16) Versions {
+	return Versions{Lowest: lowest, Highest: math.MaxInt16}
+}
+
+func ParseVersions(str string) (Versions, error) {
+	str = strings.TrimSpace(str)
+	if str == "" || str == "none" {
+		return NoVersions, nil
+	}
+	if strings.HasSuffix(str, "+") {
+		lowest, err := parseVersion(strings.TrimSuffix(str, "+"))
+		if err != nil {
+			return NoVersions, fmt.Errorf("invalid versions '%s': %v", str, err)
+		}
+		return Versions{Lowest: lowest, Highest: math.MaxInt16}, nil
+	}
+	if i := strings.Index(str, "-"); i >= 0 {
+		lowest, err := parseVersion(str[:i])
+		if err != nil {
+			return NoVersions, fmt.Errorf("invalid versions '%s': %v", str, err)
+		}
+		highest, err := parseVersion(str[i+1:])
+		if err != nil {
+			return NoVersions, fmt.Errorf("invalid versions '%s': %v", str, err)
+		}
+		if highest < lowest {
+			return NoVersions, fmt.Errorf("invalid versions '%s': %d is lower than %d", str, highest, lowest)
+		}
+		return Versions{Lowest: lowest, Highest: highest}, nil
+	}
+	version, err := parseVersion(str)
+	if err != nil {
+		return NoVersions, fmt.Errorf("invalid versions '%s': %v", str, err)
+	}
+	return Versions{Lowest: version, Highest: version}, nil
+}
+
+func parseVersion(str string) (int16, error) {
+	version, err := strconv.ParseInt(strings.TrimSpace(str), 10, 16)
+	if err != nil {
+		return 0, err
+	}
+	if version < 0 {
+		return 0, fmt.Errorf("version %d cannot be negative", version)
+	}
+	return int16(version), nil
+}
+
+func (v Versions) IsEmpty() bool {
+	return v.Lowest > v.Highest
+}
+
+func (v Versions) Contains(version int16) bool {
+	return version >= v.Lowest && version <= v.Highest
+}
+
+// Intersect returns the versions contained in both ranges
+func (v Versions) Intersect(other Versions) Versions {
+	lowest, highest := v.Lowest, v.Highest
+	if other.Lowest > lowest {
+		lowest = other.Lowest
+	}
+	if other.Highest < highest {
+		highest = other.Highest
+	}
+	if lowest > highest {
+		return NoVersions
+	}
+	return Versions{Lowest: lowest, Highest: highest}
+}
+
+func (v Versions) String() string {
+	switch {
+	case v.IsEmpty():
+		return "none"
+	case v.Highest == math.MaxInt16:
+		return fmt.Sprintf("%d+", v.Lowest)
+	case v.Lowest == v.Highest:
+		return strconv.Itoa(int(v.Lowest))
+	default:
+		return fmt.Sprintf("%d-%d", v.Lowest, v.Highest)
+	}
+}
@@ -0,0 +1,65 @@
+package kafkaschema
+
+import (
+	"errors"
+	"strconv"
+)
+
+// SkipType is returned by a WalkFunc to skip the types nested in the type it was called for
+var SkipType = errors.New("skip this type")
+
+// WalkFunc is called by Walk for every type. The path is made of the names of the fields
+// from the root, separated by dots, with "[]" for array elements, e.g. "members[].member_id".
+// field is the field declaring the type, nil for the root type and for array elements.
+type WalkFunc func(path string, field *Field, t Type) error
+
+// Walk calls fn for t and then for every type nested in it, in the order of the fields.
+// The fields of a tagged fields section are named by their field name. Walking stops at
+// the first error returned by fn, which is returned by Walk unless it is SkipType.
+func Walk(t Type, fn WalkFunc) error {
+	err := walk("", nil, t, fn)
+	if err == SkipType {
+		return nil
+	}
+	return err
+}
+
+func walk(path string, field *Field, t Type, fn WalkFunc) error {
+	if err := fn(path, field, t); err != nil {
+		if err == SkipType {
+			return nil
+		}
+		return err
+	}
+	switch tt := t.(type) {
+	case *Schema:
+		for _, f := range tt.fields {
+			if err := walk(join(path, f.def.name), f.def, f.def.t, fn); err != nil {
+				return err
+			}
+		}
+	case *ArrayOf:
+		return walk(path+"[]", nil, tt.t, fn)
+	case *CompactArrayOf:
+		return walk(path+"[]", nil, tt.t, fn)
+	case *TaggedFields:
+		for _, tag := range tt.Tags() {
+			f := tt.fields[tag]
+			name := f.name
+			if name == "" {
+				name = strconv.Itoa(tag)
+			}
+			if err := walk(join(path, name), f, f.t, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,103 @@
+package kafkaschema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newWalkSchema(t *testing.T) *Schema {
+	t.Helper()
+	member, err := NewSchema(NewField("id", STRING), NewField("topics", NewArrayOf(STRING)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := NewSchema(
+		NewField2("name", STRING, "The name of the group."),
+		NewField1("epoch", INT32, "", int32(-1)),
+		NewField("members", NewCompactArrayOf(member)),
+		NewTaggedFieldsSection(map[int]*Field{2: NewField("leader", member)}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sch
+}
+
+func TestWalk(t *testing.T) {
+	sch := newWalkSchema(t)
+	tests := []struct {
+		name string
+		fn   func(paths *[]string) WalkFunc
+		want []string
+		err  error
+	}{
+		{name: "every type", fn: func(paths *[]string) WalkFunc {
+			return func(path string, field *Field, t Type) error {
+				*paths = append(*paths, path+"="+TypeName(t))
+				return nil
+			}
+		}, want: []string{
+			"={name:STRING,epoch:INT32,members:COMPACT_ARRAY({id:STRING,topics:ARRAY(STRING)}),_tagged_fields:TAGGED_FIELDS}",
+			"name=STRING", "epoch=INT32",
+			"members=COMPACT_ARRAY({id:STRING,topics:ARRAY(STRING)})", "members[]={id:STRING,topics:ARRAY(STRING)}",
+			"members[].id=STRING", "members[].topics=ARRAY(STRING)", "members[].topics[]=STRING",
+			"_tagged_fields=TAGGED_FIELDS", "_tagged_fields.leader={id:STRING,topics:ARRAY(STRING)}",
+			"_tagged_fields.leader.id=STRING", "_tagged_fields.leader.topics=ARRAY(STRING)", "_tagged_fields.leader.topics[]=STRING",
+		}},
+		{name: "skip arrays", fn: func(paths *[]string) WalkFunc {
+			return func(path string, field *Field, t Type) error {
+				*paths = append(*paths, path)
+				if _, ok := t.(*CompactArrayOf); ok {
+					return SkipType
+				}
+				if _, ok := t.(*TaggedFields); ok {
+					return SkipType
+				}
+				return nil
+			}
+		}, want: []string{"", "name", "epoch", "members", "_tagged_fields"}},
+		{name: "stop at an error", fn: func(paths *[]string) WalkFunc {
+			return func(path string, field *Field, t Type) error {
+				*paths = append(*paths, path)
+				if path == "epoch" {
+					return ErrTypeMismatch
+				}
+				return nil
+			}
+		}, want: []string{"", "name", "epoch"}, err: ErrTypeMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			if err := Walk(sch, tt.fn(&paths)); !errors.Is(err, tt.err) {
+				t.Errorf("Walk() error = %v, want %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("Walk() visited %q, want %q", paths, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaFields(t *testing.T) {
+	sch := newWalkSchema(t)
+	fields := sch.Fields()
+	if len(fields) != 4 {
+		t.Fatalf("Fields() = %v, want 4 fields", fields)
+	}
+	name, epoch := fields[0], fields[1]
+	if name.Name() != "name" || name.Doc() != "The name of the group." || name.HasDefault() || name.Index() != 0 || name.Schema() != sch {
+		t.Errorf("Fields()[0] = %v, want the field name without default at index 0", name)
+	}
+	if v, ok := epoch.Default(); !ok || v != int32(-1) || epoch.Type() != INT32 {
+		t.Errorf("Default() of epoch = %v, %v, want -1", v, ok)
+	}
+	if IsNullable(name.Type()) || !IsNullable(NullableString) {
+		t.Error("IsNullable() does not tell nullable types")
+	}
+	fields[0] = nil
+	if sch.Fields()[0] != name {
+		t.Error("Fields() returns the fields of the schema instead of a copy")
+	}
+}
@@ -75,3 +75,26 @@ func (b *bits) makeInt64(b7, b6, b5, b4, b3, b2, b1, b0 byte) int64 {
 			(ub1 & 0xff << 8) |
 			(ub0 & 0xff))
 }
+
+func (b *bits) putInt16(buf *ByteBuffer, index int, x int16) {
+	buf.put(index, byte(x>>8))
+	buf.put(index+1, byte(x))
+}
+
+func (b *bits) putInt32(buf *ByteBuffer, index int, x int32) {
+	buf.put(index, byte(x>>24))
+	buf.put(index+1, byte(x>>16))
+	buf.put(index+2, byte(x>>8))
+	buf.put(index+3, byte(x))
+}
+
+func (b *bits) putInt64(buf *ByteBuffer, index int, x int64) {
+	buf.put(index, byte(x>>56))
+	buf.put(index+1, byte(x>>48))
+	buf.put(index+2, byte(x>>40))
+	buf.put(index+3, byte(x>>32))
+	buf.put(index+4, byte(x>>24))
+	buf.put(index+5, byte(x>>16))
+	buf.put(index+6, byte(x>>8))
+	buf.put(index+7, byte(x))
+}
@@ -1,16 +1,26 @@
 package buffer
 
 import (
+	"errors"
 	"fmt"
+	"math"
+)
+
+var (
+	// ErrBufferUnderflow is returned when a get needs more bytes than remain in the buffer
+	ErrBufferUnderflow = errors.New("buffer underflow")
+	// ErrBufferOverflow is returned when a put needs more bytes than remain in the buffer
+	ErrBufferOverflow = errors.New("buffer overflow")
 )
 
 type ByteBuffer struct {
-	buffer   []byte
-	mark     int
-	position int
-	limit    int
-	capacity int
-	offset   int
+	buffer     []byte
+	mark       int
+	position   int
+	limit      int
+	capacity   int
+	offset     int
+	autoExpand bool
 }
 
 func NewByteBuffer(buf []byte) *ByteBuffer {
@@ -28,6 +38,19 @@ func newByteBuffer(mark, pos, lim, cap, offset int, buf []byte) *ByteBuffer {
 	}
 }
 
+func Allocate(capacity int) *ByteBuffer {
+	return newByteBuffer(-1, 0, capacity, capacity, 0, make([]byte, capacity))
+}
+
+// AllocateExpandable returns a buffer which grows its capacity instead of failing
+// with an overflow when a put does not fit in the remaining bytes, a limit set below
+// the capacity still bounds the puts.
+func AllocateExpandable(initialCapacity int) *ByteBuffer {
+	b := Allocate(initialCapacity)
+	b.autoExpand = true
+	return b
+}
+
 func Wrap(array []byte) *ByteBuffer {
 	return wrap(array, 0, len(array))
 }
@@ -36,6 +59,15 @@ func wrap(array []byte, offset, length int) *ByteBuffer {
 	return newByteBuffer(-1, offset, offset+length, len(array), 0, array)
 }
 
+func (b *ByteBuffer) GetInt8() (int8, error) {
+	bt, err := b.Get()
+	return int8(bt), err
+}
+
+func (b *ByteBuffer) PutInt8(x int8) error {
+	return b.PutByte(byte(x))
+}
+
 func (b *ByteBuffer) GetInt16() (int16, error) {
 	index, err := b.nextGetIndex(2)
 	if err != nil {
@@ -47,7 +79,7 @@ func (b *ByteBuffer) GetInt16() (int16, error) {
 func (b *ByteBuffer) GetInt32() (int32, error) {
 	index, err := b.nextGetIndex(4)
 	if err != nil {
-		return -1, fmt.Errorf(fmt.Sprintf("ByteBuffer get int32 failed: %s", err))
+		return -1, fmt.Errorf("ByteBuffer get int32 failed: %w", err)
 	}
 	return Bits.getInt32(b, b.ix(index)), nil
 }
@@ -60,6 +92,104 @@ func (b *ByteBuffer) GetInt64() (int64, error) {
 	return Bits.getInt64(b, b.ix(index)), nil
 }
 
+func (b *ByteBuffer) PutInt16(x int16) error {
+	index, err := b.nextPutIndex(2)
+	if err != nil {
+		return err
+	}
+	Bits.putInt16(b, b.ix(index), x)
+	return nil
+}
+
+func (b *ByteBuffer) PutInt32(x int32) error {
+	index, err := b.nextPutIndex(4)
+	if err != nil {
+		return err
+	}
+	Bits.putInt32(b, b.ix(index), x)
+	return nil
+}
+
+func (b *ByteBuffer) PutInt64(x int64) error {
+	index, err := b.nextPutIndex(8)
+	if err != nil {
+		return err
+	}
+	Bits.putInt64(b, b.ix(index), x)
+	return nil
+}
+
+func (b *ByteBuffer) Get() (byte, error) {
+	index, err := b.nextGetIndex(1)
+	if err != nil {
+		return 0, err
+	}
+	return b.get(b.ix(index)), nil
+}
+
+func (b *ByteBuffer) PutByte(x byte) error {
+	index, err := b.nextPutIndex(1)
+	if err != nil {
+		return err
+	}
+	b.put(b.ix(index), x)
+	return nil
+}
+
+// Put copies the remaining bytes of src into the buffer, advancing the position of both buffers
+func (b *ByteBuffer) Put(src *ByteBuffer) error {
+	if src == b {
+		return fmt.Errorf("illegal argument exception: the source buffer is this buffer")
+	}
+	n := src.Remaining()
+	index, err := b.nextPutIndex(n)
+	if err != nil {
+		return err
+	}
+	copy(b.buffer[b.ix(index):b.ix(index+n)], src.buffer[src.ix(src.position):src.ix(src.limit)])
+	src.position = src.limit
+	return nil
+}
+
+// PutString writes the UTF-8 bytes of str without any length prefix
+func (b *ByteBuffer) PutString(str string) error {
+	index, err := b.nextPutIndex(len(str))
+	if err != nil {
+		return err
+	}
+	copy(b.buffer[b.ix(index):], str)
+	return nil
+}
+
+func (b *ByteBuffer) GetFloat64() (float64, error) {
+	bits, err := b.GetInt64()
+	if err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(uint64(bits)), nil
+}
+
+func (b *ByteBuffer) PutFloat64(x float64) error {
+	return b.PutInt64(int64(math.Float64bits(x)))
+}
+
+// PutBytes copies src into the buffer at the current position and advances it
+func (b *ByteBuffer) PutBytes(src []byte) error {
+	index, err := b.nextPutIndex(len(src))
+	if err != nil {
+		return err
+	}
+	copy(b.buffer[b.ix(index):], src)
+	return nil
+}
+
+// Bytes returns a copy of the bytes between position and limit, the position is not changed
+func (b *ByteBuffer) Bytes() []byte {
+	bs := make([]byte, b.Remaining())
+	copy(bs, b.buffer[b.ix(b.position):b.ix(b.limit)])
+	return bs
+}
+
 func (b *ByteBuffer) GetString(offset, length int) (string, error) {
 	array, err := b.array()
 	if err != nil {
@@ -77,9 +207,89 @@ func (b *ByteBuffer) Remaining() int {
 	return b.limit - b.position
 }
 
+func (b *ByteBuffer) HasRemaining() bool {
+	return b.position < b.limit
+}
+
+func (b *ByteBuffer) GetLimit() int {
+	return b.limit
+}
+
+func (b *ByteBuffer) GetCapacity() int {
+	return b.capacity
+}
+
+func (b *ByteBuffer) IsAutoExpand() bool {
+	return b.autoExpand
+}
+
+// SetAutoExpand switches the buffer between failing and growing when a put overflows it
+func (b *ByteBuffer) SetAutoExpand(autoExpand bool) {
+	b.autoExpand = autoExpand
+}
+
+// Mark sets the buffer's mark at its position
+func (b *ByteBuffer) Mark() {
+	b.mark = b.position
+}
+
+// Reset resets the buffer's position to the previously-marked position
+func (b *ByteBuffer) Reset() error {
+	if b.mark < 0 {
+		return fmt.Errorf("invalid mark exception")
+	}
+	b.position = b.mark
+	return nil
+}
+
+// Clear makes the buffer ready for a new sequence of puts: the limit is set to the
+// capacity, the position to zero and the mark is discarded
+func (b *ByteBuffer) Clear() {
+	b.position = 0
+	b.limit = b.capacity
+	b.mark = -1
+}
+
+// Flip makes the buffer ready for a sequence of gets of what has just been put:
+// the limit is set to the current position, the position to zero and the mark is discarded
+func (b *ByteBuffer) Flip() {
+	b.limit = b.position
+	b.position = 0
+	b.mark = -1
+}
+
+// Rewind sets the position to zero and discards the mark, the limit is unchanged
+func (b *ByteBuffer) Rewind() {
+	b.position = 0
+	b.mark = -1
+}
+
+// Compact copies the remaining bytes to the beginning of the buffer and makes it
+// ready for further puts after them
+func (b *ByteBuffer) Compact() {
+	remaining := b.Remaining()
+	copy(b.buffer[b.ix(0):b.ix(remaining)], b.buffer[b.ix(b.position):b.ix(b.limit)])
+	b.position = remaining
+	b.limit = b.capacity
+	b.mark = -1
+}
+
+// Duplicate returns a buffer sharing this buffer's content with independent position, limit and mark
+func (b *ByteBuffer) Duplicate() *ByteBuffer {
+	dup := newByteBuffer(b.mark, b.position, b.limit, b.capacity, b.offset, b.buffer)
+	dup.autoExpand = b.autoExpand
+	return dup
+}
+
 func (b *ByteBuffer) GetPosition() int {
 	return b.position
 }
+
+// AbsolutePosition returns the position as an index in the backing array, for a buffer
+// obtained with Slice it is the position in the buffer it was sliced from
+func (b *ByteBuffer) AbsolutePosition() int {
+	return b.ix(b.position)
+}
 func (b *ByteBuffer) SetPosition(newPosition int) error {
 	if (newPosition > b.limit) || (newPosition < 0) {
 		return fmt.Errorf("illegal argument exception")
@@ -111,13 +321,44 @@ func (b *ByteBuffer) SetLimit(newLimit int) error {
 
 func (b *ByteBuffer) nextGetIndex(nb int) (int, error) {
 	if b.limit-b.position < nb {
-		return -1, fmt.Errorf("buffer Underflow Exception")
+		return -1, ErrBufferUnderflow
 	}
 	p := b.position
 	b.position = p + nb
 	return p, nil
 }
 
+func (b *ByteBuffer) nextPutIndex(nb int) (int, error) {
+	// a limit set below the capacity bounds the puts, the buffer grows past its capacity only
+	if b.limit-b.position < nb && b.autoExpand && b.limit == b.capacity {
+		b.expand(b.position + nb)
+	}
+	if b.limit-b.position < nb {
+		return -1, ErrBufferOverflow
+	}
+	p := b.position
+	b.position = p + nb
+	return p, nil
+}
+
+// expand grows the capacity to at least minCapacity. The content is moved to a new
+// array, so slices taken before the expansion no longer share it with this buffer. A
+// limit set below the capacity is kept, a limit at the capacity grows with it.
+func (b *ByteBuffer) expand(minCapacity int) {
+	newCapacity := b.capacity * 2
+	if newCapacity < minCapacity {
+		newCapacity = minCapacity
+	}
+	newBuffer := make([]byte, newCapacity)
+	copy(newBuffer, b.buffer[b.ix(0):b.ix(b.capacity)])
+	b.buffer = newBuffer
+	b.offset = 0
+	if b.limit == b.capacity {
+		b.limit = newCapacity
+	}
+	b.capacity = newCapacity
+}
+
 func (b *ByteBuffer) ix(i int) int {
 	return i + b.offset
 }
@@ -126,6 +367,10 @@ func (b *ByteBuffer) get(index int) byte {
 	return b.buffer[index]
 }
 
+func (b *ByteBuffer) put(index int, x byte) {
+	b.buffer[index] = x
+}
+
 func (b *ByteBuffer) array() ([]byte, error) {
 	if b.buffer == nil {
 		return nil, fmt.Errorf("unsupported operation exception")
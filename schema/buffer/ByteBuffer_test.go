@@ -0,0 +1,143 @@
+package buffer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestByteBufferRoundTrip(t *testing.T) {
+	b := Allocate(64)
+	if err := b.PutInt8(-8); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.PutInt16(-16); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.PutInt32(-32); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.PutInt64(-64); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.PutFloat64(1.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.PutBytes([]byte("ab")); err != nil {
+		t.Fatal(err)
+	}
+	b.Flip()
+
+	if v, err := b.GetInt8(); err != nil || v != -8 {
+		t.Errorf("GetInt8() = %v, %v, want -8", v, err)
+	}
+	if v, err := b.GetInt16(); err != nil || v != -16 {
+		t.Errorf("GetInt16() = %v, %v, want -16", v, err)
+	}
+	if v, err := b.GetInt32(); err != nil || v != -32 {
+		t.Errorf("GetInt32() = %v, %v, want -32", v, err)
+	}
+	if v, err := b.GetInt64(); err != nil || v != -64 {
+		t.Errorf("GetInt64() = %v, %v, want -64", v, err)
+	}
+	if v, err := b.GetFloat64(); err != nil || v != 1.5 {
+		t.Errorf("GetFloat64() = %v, %v, want 1.5", v, err)
+	}
+	if v := b.Bytes(); !bytes.Equal(v, []byte("ab")) {
+		t.Errorf("Bytes() = %v, want ab", v)
+	}
+}
+
+func TestByteBufferUnderflowOverflow(t *testing.T) {
+	b := Allocate(3)
+	if err := b.PutInt32(1); !errors.Is(err, ErrBufferOverflow) {
+		t.Errorf("PutInt32() on 3 bytes = %v, want ErrBufferOverflow", err)
+	}
+	if b.GetPosition() != 0 {
+		t.Errorf("position after overflow = %d, want 0", b.GetPosition())
+	}
+	if _, err := b.GetInt32(); !errors.Is(err, ErrBufferUnderflow) {
+		t.Errorf("GetInt32() on 3 bytes = %v, want ErrBufferUnderflow", err)
+	}
+}
+
+func TestByteBufferPutOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		put  func(b *ByteBuffer) error
+	}{
+		{name: "PutByte", put: func(b *ByteBuffer) error { return b.PutByte(1) }},
+		{name: "PutInt8", put: func(b *ByteBuffer) error { return b.PutInt8(1) }},
+		{name: "PutInt16", put: func(b *ByteBuffer) error { return b.PutInt16(1) }},
+		{name: "PutInt32", put: func(b *ByteBuffer) error { return b.PutInt32(1) }},
+		{name: "PutInt64", put: func(b *ByteBuffer) error { return b.PutInt64(1) }},
+		{name: "PutFloat64", put: func(b *ByteBuffer) error { return b.PutFloat64(1) }},
+		{name: "PutBytes", put: func(b *ByteBuffer) error { return b.PutBytes([]byte("a")) }},
+		{name: "PutString", put: func(b *ByteBuffer) error { return b.PutString("a") }},
+		{name: "Put", put: func(b *ByteBuffer) error { return b.Put(Wrap([]byte("a"))) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.put(Allocate(0)); err != ErrBufferOverflow {
+				t.Errorf("%s() on a full buffer = %v, want ErrBufferOverflow", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestByteBufferExpand(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        int
+		put          int
+		wantErr      error
+		wantLimit    int
+		wantCapacity int
+	}{
+		{name: "limit at capacity grows", limit: 4, put: 6, wantLimit: 8, wantCapacity: 8},
+		{name: "grows to the put", limit: 4, put: 20, wantLimit: 20, wantCapacity: 20},
+		{name: "fits without growing", limit: 4, put: 4, wantLimit: 4, wantCapacity: 4},
+		{name: "limit below capacity is kept", limit: 2, put: 3, wantErr: ErrBufferOverflow, wantLimit: 2, wantCapacity: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := AllocateExpandable(4)
+			if err := b.SetLimit(tt.limit); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.PutBytes(make([]byte, tt.put)); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PutBytes(%d) = %v, want %v", tt.put, err, tt.wantErr)
+			}
+			if b.GetLimit() != tt.wantLimit || b.GetCapacity() != tt.wantCapacity {
+				t.Errorf("limit, capacity = %d, %d, want %d, %d", b.GetLimit(), b.GetCapacity(), tt.wantLimit, tt.wantCapacity)
+			}
+		})
+	}
+}
+
+func TestByteBufferExpandKeepsContent(t *testing.T) {
+	b := AllocateExpandable(2)
+	for i := 0; i < 10; i++ {
+		if err := b.PutByte(byte(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.Flip()
+	if v := b.Bytes(); !bytes.Equal(v, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("Bytes() = %v", v)
+	}
+}
+
+func TestSliceAbsolutePosition(t *testing.T) {
+	b := Wrap([]byte{1, 2, 3, 4})
+	if _, err := b.GetInt16(); err != nil {
+		t.Fatal(err)
+	}
+	s := b.Slice()
+	if s.GetPosition() != 0 || s.AbsolutePosition() != 2 {
+		t.Errorf("slice position, absolute position = %d, %d, want 0, 2", s.GetPosition(), s.AbsolutePosition())
+	}
+	if v, err := s.GetInt16(); err != nil || v != 0x0304 {
+		t.Errorf("GetInt16() on the slice = %v, %v, want 0x0304", v, err)
+	}
+}
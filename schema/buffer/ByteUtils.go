@@ -0,0 +1,118 @@
+package buffer
+
+import "fmt"
+
+// GetUnsignedVarint reads an integer stored in variable-length format using unsigned
+// decoding from http://code.google.com/apis/protocolbuffers/docs/encoding.html
+func (b *ByteBuffer) GetUnsignedVarint() (int32, error) {
+	var value uint32
+	var i uint
+	for {
+		bt, err := b.Get()
+		if err != nil {
+			return 0, fmt.Errorf("malformed variable-length value: %w", err)
+		}
+		if bt&0x80 == 0 {
+			if i == 28 && bt > 0x0f {
+				return 0, fmt.Errorf("varint overflows a 32-bit integer, the 5th byte is %x", bt)
+			}
+			value |= uint32(bt) << i
+			return int32(value), nil
+		}
+		value |= uint32(bt&0x7f) << i
+		i += 7
+		if i > 28 {
+			return 0, fmt.Errorf("varint is too long, the most significant bit in the 5th byte is set, converted value: %x", value)
+		}
+	}
+}
+
+// PutUnsignedVarint writes an integer in variable-length format using unsigned encoding
+func (b *ByteBuffer) PutUnsignedVarint(value int32) error {
+	v := uint32(value)
+	for v&0xffffff80 != 0 {
+		if err := b.PutByte(byte(v&0x7f | 0x80)); err != nil {
+			return err
+		}
+		v >>= 7
+	}
+	return b.PutByte(byte(v))
+}
+
+// SizeOfUnsignedVarint returns the number of bytes needed to encode an integer in unsigned variable-length format
+func SizeOfUnsignedVarint(value int32) int {
+	v := uint32(value)
+	bytes := 1
+	for v&0xffffff80 != 0 {
+		bytes++
+		v >>= 7
+	}
+	return bytes
+}
+
+// GetVarint reads an integer stored in variable-length format using zig-zag decoding
+func (b *ByteBuffer) GetVarint() (int32, error) {
+	value, err := b.GetUnsignedVarint()
+	if err != nil {
+		return 0, err
+	}
+	v := uint32(value)
+	return int32(v>>1) ^ -int32(v&1), nil
+}
+
+// PutVarint writes an integer in variable-length format using zig-zag encoding
+func (b *ByteBuffer) PutVarint(value int32) error {
+	return b.PutUnsignedVarint((value << 1) ^ (value >> 31))
+}
+
+// GetVarlong reads a long stored in variable-length format using zig-zag decoding
+func (b *ByteBuffer) GetVarlong() (int64, error) {
+	var value uint64
+	var i uint
+	for {
+		bt, err := b.Get()
+		if err != nil {
+			return 0, fmt.Errorf("malformed variable-length value: %w", err)
+		}
+		if bt&0x80 == 0 {
+			if i == 63 && bt > 0x01 {
+				return 0, fmt.Errorf("varlong overflows a 64-bit integer, the 10th byte is %x", bt)
+			}
+			value |= uint64(bt) << i
+			return int64(value>>1) ^ -int64(value&1), nil
+		}
+		value |= uint64(bt&0x7f) << i
+		i += 7
+		if i > 63 {
+			return 0, fmt.Errorf("varlong is too long, most significant bit in the 10th byte is set, converted value: %x", value)
+		}
+	}
+}
+
+// PutVarlong writes a long in variable-length format using zig-zag encoding
+func (b *ByteBuffer) PutVarlong(value int64) error {
+	v := uint64((value << 1) ^ (value >> 63))
+	for v&0xffffffffffffff80 != 0 {
+		if err := b.PutByte(byte(v&0x7f | 0x80)); err != nil {
+			return err
+		}
+		v >>= 7
+	}
+	return b.PutByte(byte(v))
+}
+
+// SizeOfVarint returns the number of bytes needed to encode an integer in zig-zag variable-length format
+func SizeOfVarint(value int32) int {
+	return SizeOfUnsignedVarint((value << 1) ^ (value >> 31))
+}
+
+// SizeOfVarlong returns the number of bytes needed to encode a long in zig-zag variable-length format
+func SizeOfVarlong(value int64) int {
+	v := uint64((value << 1) ^ (value >> 63))
+	bytes := 1
+	for v&0xffffffffffffff80 != 0 {
+		bytes++
+		v >>= 7
+	}
+	return bytes
+}
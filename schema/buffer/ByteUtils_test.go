@@ -0,0 +1,141 @@
+package buffer
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestUnsignedVarint(t *testing.T) {
+	tests := []struct {
+		value int32
+		want  string
+	}{
+		{0, "00"},
+		{1, "01"},
+		{127, "7f"},
+		{128, "8001"},
+		{16383, "ff7f"},
+		{16384, "808001"},
+		{math.MaxInt32, "ffffffff07"},
+		{-1, "ffffffff0f"},
+		{math.MinInt32, "8080808008"},
+	}
+	for _, tt := range tests {
+		b := Allocate(5)
+		if err := b.PutUnsignedVarint(tt.value); err != nil {
+			t.Fatalf("PutUnsignedVarint(%d) error = %v", tt.value, err)
+		}
+		b.Flip()
+		if got := hex.EncodeToString(b.Bytes()); got != tt.want {
+			t.Errorf("PutUnsignedVarint(%d) = %s, want %s", tt.value, got, tt.want)
+		}
+		if size := SizeOfUnsignedVarint(tt.value); size != len(tt.want)/2 {
+			t.Errorf("SizeOfUnsignedVarint(%d) = %d, want %d", tt.value, size, len(tt.want)/2)
+		}
+		if got, err := b.GetUnsignedVarint(); err != nil || got != tt.value {
+			t.Errorf("GetUnsignedVarint() = %d, %v, want %d", got, err, tt.value)
+		}
+	}
+}
+
+func TestVarint(t *testing.T) {
+	tests := []struct {
+		value int32
+		want  string
+	}{
+		{0, "00"},
+		{-1, "01"},
+		{1, "02"},
+		{63, "7e"},
+		{-64, "7f"},
+		{64, "8001"},
+		{math.MaxInt32, "feffffff0f"},
+		{math.MinInt32, "ffffffff0f"},
+	}
+	for _, tt := range tests {
+		b := Allocate(5)
+		if err := b.PutVarint(tt.value); err != nil {
+			t.Fatalf("PutVarint(%d) error = %v", tt.value, err)
+		}
+		b.Flip()
+		if got := hex.EncodeToString(b.Bytes()); got != tt.want {
+			t.Errorf("PutVarint(%d) = %s, want %s", tt.value, got, tt.want)
+		}
+		if size := SizeOfVarint(tt.value); size != len(tt.want)/2 {
+			t.Errorf("SizeOfVarint(%d) = %d, want %d", tt.value, size, len(tt.want)/2)
+		}
+		if got, err := b.GetVarint(); err != nil || got != tt.value {
+			t.Errorf("GetVarint() = %d, %v, want %d", got, err, tt.value)
+		}
+	}
+}
+
+func TestVarlong(t *testing.T) {
+	tests := []struct {
+		value int64
+		want  string
+	}{
+		{0, "00"},
+		{-1, "01"},
+		{1, "02"},
+		{math.MaxInt32 + 1, "8080808010"},
+		{math.MaxInt64, "feffffffffffffffff01"},
+		{math.MinInt64, "ffffffffffffffffff01"},
+	}
+	for _, tt := range tests {
+		b := Allocate(10)
+		if err := b.PutVarlong(tt.value); err != nil {
+			t.Fatalf("PutVarlong(%d) error = %v", tt.value, err)
+		}
+		b.Flip()
+		if got := hex.EncodeToString(b.Bytes()); got != tt.want {
+			t.Errorf("PutVarlong(%d) = %s, want %s", tt.value, got, tt.want)
+		}
+		if size := SizeOfVarlong(tt.value); size != len(tt.want)/2 {
+			t.Errorf("SizeOfVarlong(%d) = %d, want %d", tt.value, size, len(tt.want)/2)
+		}
+		if got, err := b.GetVarlong(); err != nil || got != tt.value {
+			t.Errorf("GetVarlong() = %d, %v, want %d", got, err, tt.value)
+		}
+	}
+}
+
+func TestMalformedVarints(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		read      func(b *ByteBuffer) error
+		underflow bool
+	}{
+		{name: "unsigned varint too long", data: "ffffffff80", read: readUnsignedVarint},
+		{name: "unsigned varint overflows", data: "ffffffff1f", read: readUnsignedVarint},
+		{name: "unsigned varint truncated", data: "ff", read: readUnsignedVarint, underflow: true},
+		{name: "varlong too long", data: "ffffffffffffffffff80", read: readVarlong},
+		{name: "varlong overflows", data: "ffffffffffffffffff02", read: readVarlong},
+		{name: "varlong truncated", data: "8080", read: readVarlong, underflow: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := hex.DecodeString(tt.data)
+			err := tt.read(Wrap(data))
+			if err == nil {
+				t.Fatal("read succeeded")
+			}
+			if errors.Is(err, ErrBufferUnderflow) != tt.underflow {
+				t.Errorf("errors.Is(%v, ErrBufferUnderflow) = %v, want %v", err, !tt.underflow, tt.underflow)
+			}
+		})
+	}
+}
+
+func readUnsignedVarint(b *ByteBuffer) error {
+	_, err := b.GetUnsignedVarint()
+	return err
+}
+
+func readVarlong(b *ByteBuffer) error {
+	_, err := b.GetVarlong()
+	return err
+}
@@ -0,0 +1,179 @@
+// Package doc renders schemas as the request and response tables of the Kafka
+// protocol guide, in Markdown or in HTML.
+package doc
+
+import (
+	"fmt"
+	"html"
+	"io"
+	kafkaschema "kafka_schema/schema"
+	"strconv"
+	"strings"
+)
+
+// Section is a documented schema, Title is usually the name and version of the schema
+type Section struct {
+	Title  string
+	Schema *kafkaschema.Schema
+}
+
+// row is a line of the field table of a schema
+type row struct {
+	path, typeName, nullable, defaultValue, doc string
+}
+
+// Markdown writes a section per schema made of a title, the BNF of the schema and a
+// table of its fields, nested fields included
+func Markdown(w io.Writer, sections ...Section) error {
+	var sb strings.Builder
+	for i, section := range sections {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		rows, err := rows(section.Schema)
+		if err != nil {
+			return fmt.Errorf("%s: %v", section.Title, err)
+		}
+		fmt.Fprintf(&sb, "### %s\n\n```\n%s```\n\n", section.Title, bnf(section.Title, section.Schema))
+		sb.WriteString("| Field | Type | Nullable | Default | Description |\n")
+		sb.WriteString("|-------|------|----------|---------|-------------|\n")
+		for _, r := range rows {
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", escapeMarkdown(r.path), escapeMarkdown(r.typeName),
+				r.nullable, escapeMarkdown(r.defaultValue), escapeMarkdown(r.doc))
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// HTML writes the sections of Markdown as an HTML fragment
+func HTML(w io.Writer, sections ...Section) error {
+	var sb strings.Builder
+	for _, section := range sections {
+		rows, err := rows(section.Schema)
+		if err != nil {
+			return fmt.Errorf("%s: %v", section.Title, err)
+		}
+		fmt.Fprintf(&sb, "<h5>%s</h5>\n<pre>%s</pre>\n", html.EscapeString(section.Title),
+			html.EscapeString(bnf(section.Title, section.Schema)))
+		sb.WriteString("<table class=\"data-table\"><tbody>\n")
+		sb.WriteString("<tr><th>Field</th><th>Type</th><th>Nullable</th><th>Default</th><th>Description</th></tr>\n")
+		for _, r := range rows {
+			fmt.Fprintf(&sb, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+				html.EscapeString(r.path), html.EscapeString(r.typeName), r.nullable,
+				html.EscapeString(r.defaultValue), html.EscapeString(r.doc))
+		}
+		sb.WriteString("</tbody></table>\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func rows(schema *kafkaschema.Schema) ([]row, error) {
+	var rows []row
+	err := kafkaschema.Walk(schema, func(path string, field *kafkaschema.Field, t kafkaschema.Type) error {
+		// the root and the array elements are described by their field
+		if field == nil {
+			return nil
+		}
+		r := row{
+			path:     path,
+			typeName: shortTypeName(t),
+			nullable: "no",
+			doc:      field.Doc(),
+		}
+		if kafkaschema.IsNullable(t) {
+			r.nullable = "yes"
+		}
+		if _, ok := t.(*kafkaschema.TaggedFields); !ok {
+			if v, ok := field.Default(); ok {
+				r.defaultValue = formatDefault(v)
+			}
+		}
+		rows = append(rows, r)
+		return nil
+	})
+	return rows, err
+}
+
+// bnf returns the grammar of the schema the way the Kafka protocol guide writes it, e.g.
+//
+//	Name => group [topics]
+//	  group => STRING
+//	  topics => topic partition
+//	    topic => STRING
+//	    partition => INT32
+func bnf(name string, schema *kafkaschema.Schema) string {
+	var sb strings.Builder
+	writeBNF(&sb, name, schema, "")
+	return sb.String()
+}
+
+func writeBNF(sb *strings.Builder, name string, schema *kafkaschema.Schema, indent string) {
+	sb.WriteString(indent + name + " =>")
+	for _, f := range schema.Fields() {
+		switch f.Type().(type) {
+		case *kafkaschema.TaggedFields:
+			sb.WriteString(" TAG_BUFFER")
+		case *kafkaschema.ArrayOf, *kafkaschema.CompactArrayOf:
+			sb.WriteString(" [" + f.Name() + "]")
+		default:
+			sb.WriteString(" " + f.Name())
+		}
+	}
+	sb.WriteString("\n")
+	for _, f := range schema.Fields() {
+		t := f.Type()
+		if _, ok := t.(*kafkaschema.TaggedFields); ok {
+			continue
+		}
+		if element, ok := elementType(t); ok {
+			t = element
+		}
+		if nested, ok := t.(*kafkaschema.Schema); ok {
+			writeBNF(sb, f.Name(), nested, indent+"  ")
+		} else {
+			sb.WriteString(indent + "  " + f.Name() + " => " + kafkaschema.TypeName(t) + "\n")
+		}
+	}
+}
+
+// shortTypeName is the TypeName of the type with the nested schemas named STRUCT
+func shortTypeName(t kafkaschema.Type) string {
+	switch tt := t.(type) {
+	case *kafkaschema.Schema:
+		return "STRUCT"
+	case *kafkaschema.ArrayOf:
+		return "ARRAY(" + shortTypeName(tt.ElementType()) + ")"
+	case *kafkaschema.CompactArrayOf:
+		return "COMPACT_ARRAY(" + shortTypeName(tt.ElementType()) + ")"
+	default:
+		return kafkaschema.TypeName(t)
+	}
+}
+
+func elementType(t kafkaschema.Type) (kafkaschema.Type, bool) {
+	switch tt := t.(type) {
+	case *kafkaschema.ArrayOf:
+		return tt.ElementType(), true
+	case *kafkaschema.CompactArrayOf:
+		return tt.ElementType(), true
+	default:
+		return nil, false
+	}
+}
+
+func formatDefault(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
@@ -0,0 +1,88 @@
+package doc
+
+import (
+	"io"
+	kafkaschema "kafka_schema/schema"
+	"strings"
+	"testing"
+)
+
+func newGroupSection(t *testing.T) Section {
+	t.Helper()
+	member, err := kafkaschema.NewSchema(kafkaschema.NewField2("id", kafkaschema.STRING, "The member | id."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := kafkaschema.NewSchema(
+		kafkaschema.NewField1("epoch", kafkaschema.INT32, "The <epoch>.", int32(-1)),
+		kafkaschema.NewField2("host", kafkaschema.NullableString, "The host."),
+		kafkaschema.NewField2("members", kafkaschema.NewArrayOf(member), "The members."),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Section{Title: "Group V0", Schema: sch}
+}
+
+func TestDocumentation(t *testing.T) {
+	section := newGroupSection(t)
+	tests := []struct {
+		name   string
+		render func(w io.Writer, sections ...Section) error
+		want   string
+	}{
+		{name: "markdown", render: Markdown, want: "### Group V0\n\n" +
+			"```\n" +
+			"Group V0 => epoch host [members]\n" +
+			"  epoch => INT32\n" +
+			"  host => NULLABLE_STRING\n" +
+			"  members => id\n" +
+			"    id => STRING\n" +
+			"```\n\n" +
+			"| Field | Type | Nullable | Default | Description |\n" +
+			"|-------|------|----------|---------|-------------|\n" +
+			"| epoch | INT32 | no | -1 | The <epoch>. |\n" +
+			"| host | NULLABLE_STRING | yes |  | The host. |\n" +
+			"| members | ARRAY(STRUCT) | no |  | The members. |\n" +
+			"| members[].id | STRING | no |  | The member \\| id. |\n"},
+		{name: "html", render: HTML, want: "<h5>Group V0</h5>\n" +
+			"<pre>Group V0 =&gt; epoch host [members]\n" +
+			"  epoch =&gt; INT32\n" +
+			"  host =&gt; NULLABLE_STRING\n" +
+			"  members =&gt; id\n" +
+			"    id =&gt; STRING\n" +
+			"</pre>\n" +
+			"<table class=\"data-table\"><tbody>\n" +
+			"<tr><th>Field</th><th>Type</th><th>Nullable</th><th>Default</th><th>Description</th></tr>\n" +
+			"<tr><td>epoch</td><td>INT32</td><td>no</td><td>-1</td><td>The &lt;epoch&gt;.</td></tr>\n" +
+			"<tr><td>host</td><td>NULLABLE_STRING</td><td>yes</td><td></td><td>The host.</td></tr>\n" +
+			"<tr><td>members</td><td>ARRAY(STRUCT)</td><td>no</td><td></td><td>The members.</td></tr>\n" +
+			"<tr><td>members[].id</td><td>STRING</td><td>no</td><td></td><td>The member | id.</td></tr>\n" +
+			"</tbody></table>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := tt.render(&sb, section); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownSeparatesSections(t *testing.T) {
+	section := newGroupSection(t)
+	var one, two strings.Builder
+	if err := Markdown(&one, section); err != nil {
+		t.Fatal(err)
+	}
+	if err := Markdown(&two, section, section); err != nil {
+		t.Fatal(err)
+	}
+	if want := one.String() + "\n" + one.String(); two.String() != want {
+		t.Errorf("Markdown() of two sections =\n%s\nwant\n%s", two.String(), want)
+	}
+}
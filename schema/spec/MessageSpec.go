@@ -0,0 +1,271 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MessageSpec is a Kafka JSON message specification, such as the files of
+// clients/src/main/resources/common/message in the Kafka sources
+type MessageSpec struct {
+	Name                string        `json:"name"`
+	Type                string        `json:"type"`
+	ApiKey              *int16        `json:"apiKey,omitempty"`
+	ValidVersionsString string        `json:"validVersions"`
+	FlexibleString      string        `json:"flexibleVersions"`
+	Fields              []*FieldSpec  `json:"fields"`
+	CommonStructs       []*StructSpec `json:"commonStructs,omitempty"`
+
+	validVersions    Versions
+	flexibleVersions Versions
+	structs          map[string]*StructSpec
+}
+
+// StructSpec is a named list of fields, either declared inline by a field of a struct
+// type or in the common structs of the message
+type StructSpec struct {
+	Name           string       `json:"name"`
+	VersionsString string       `json:"versions"`
+	Fields         []*FieldSpec `json:"fields"`
+}
+
+type FieldSpec struct {
+	Name                   string          `json:"name"`
+	Type                   string          `json:"type"`
+	VersionsString         string          `json:"versions"`
+	NullableVersionsString string          `json:"nullableVersions,omitempty"`
+	TaggedVersionsString   string          `json:"taggedVersions,omitempty"`
+	FlexibleVersionsString string          `json:"flexibleVersions,omitempty"`
+	Tag                    *int            `json:"tag,omitempty"`
+	Default                json.RawMessage `json:"default,omitempty"`
+	Ignorable              bool            `json:"ignorable,omitempty"`
+	MapKey                 bool            `json:"mapKey,omitempty"`
+	EntityType             string          `json:"entityType,omitempty"`
+	About                  string          `json:"about,omitempty"`
+	Fields                 []*FieldSpec    `json:"fields,omitempty"`
+
+	versions         Versions
+	nullableVersions Versions
+	taggedVersions   Versions
+}
+
+// Parse parses a message specification. The line comments Kafka puts in its
+// specifications, starting with "//", are allowed.
+func Parse(data []byte) (*MessageSpec, error) {
+	message := &MessageSpec{}
+	if err := json.Unmarshal(stripComments(data), message); err != nil {
+		return nil, fmt.Errorf("invalid message specification: %v", err)
+	}
+	if err := message.init(); err != nil {
+		return nil, fmt.Errorf("invalid message specification '%s': %v", message.Name, err)
+	}
+	return message, nil
+}
+
+// Load parses the message specification stored in the file at path
+func Load(path string) (*MessageSpec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// LoadFS parses the message specification stored in the file name of fsys, e.g. the
+// specifications embedded in a package
+func LoadFS(fsys fs.FS, name string) (*MessageSpec, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+func (m *MessageSpec) ValidVersions() Versions {
+	return m.validVersions
+}
+
+func (m *MessageSpec) FlexibleVersions() Versions {
+	return m.flexibleVersions
+}
+
+// Struct returns the struct spec declaring the fields of the struct type name
+func (m *MessageSpec) Struct(name string) (*StructSpec, bool) {
+	s, ok := m.structs[name]
+	return s, ok
+}
+
+// Structs returns the struct specs of the message ordered by name
+func (m *MessageSpec) Structs() []*StructSpec {
+	structs := make([]*StructSpec, 0, len(m.structs))
+	for _, s := range m.structs {
+		structs = append(structs, s)
+	}
+	sort.Slice(structs, func(i, j int) bool {
+		return structs[i].Name < structs[j].Name
+	})
+	return structs
+}
+
+func (m *MessageSpec) init() (err error) {
+	if m.Name == "" {
+		return fmt.Errorf("the message has no name")
+	}
+	if m.validVersions, err = ParseVersions(m.ValidVersionsString); err != nil {
+		return err
+	}
+	if m.validVersions.IsEmpty() {
+		return fmt.Errorf("the message has no valid versions")
+	}
+	if m.validVersions.Highest == math.MaxInt16 {
+		return fmt.Errorf("the valid versions %s have no highest version", m.validVersions)
+	}
+	if m.flexibleVersions, err = ParseVersions(m.FlexibleString); err != nil {
+		return err
+	}
+	m.structs = make(map[string]*StructSpec)
+	for _, s := range m.CommonStructs {
+		if err = m.addStruct(s); err != nil {
+			return err
+		}
+		if err = m.initFields(s.Fields); err != nil {
+			return fmt.Errorf("struct %s: %v", s.Name, err)
+		}
+	}
+	return m.initFields(m.Fields)
+}
+
+func (m *MessageSpec) addStruct(s *StructSpec) error {
+	if _, ok := m.structs[s.Name]; ok {
+		return fmt.Errorf("struct %s is declared twice", s.Name)
+	}
+	m.structs[s.Name] = s
+	return nil
+}
+
+func (m *MessageSpec) initFields(fields []*FieldSpec) (err error) {
+	names := make(map[string]bool)
+	for _, f := range fields {
+		if names[f.Name] {
+			return fmt.Errorf("field %s is declared twice", f.Name)
+		}
+		names[f.Name] = true
+		if f.versions, err = ParseVersions(f.VersionsString); err != nil {
+			return fmt.Errorf("field %s: %v", f.Name, err)
+		}
+		if f.nullableVersions, err = ParseVersions(f.NullableVersionsString); err != nil {
+			return fmt.Errorf("field %s: %v", f.Name, err)
+		}
+		if f.taggedVersions, err = ParseVersions(f.TaggedVersionsString); err != nil {
+			return fmt.Errorf("field %s: %v", f.Name, err)
+		}
+		if !f.taggedVersions.IsEmpty() && f.Tag == nil {
+			return fmt.Errorf("field %s has tagged versions but no tag", f.Name)
+		}
+		if f.IsStruct() && len(f.Fields) > 0 {
+			if err = m.addStruct(&StructSpec{Name: f.ElementType(), VersionsString: f.VersionsString, Fields: f.Fields}); err != nil {
+				return err
+			}
+		}
+		if len(f.Fields) > 0 {
+			if err = m.initFields(f.Fields); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *FieldSpec) Versions() Versions {
+	return f.versions
+}
+
+func (f *FieldSpec) NullableVersions() Versions {
+	return f.nullableVersions
+}
+
+func (f *FieldSpec) TaggedVersions() Versions {
+	return f.taggedVersions
+}
+
+// SnakeCaseName returns the field name as used by the schemas, e.g. "commit_timestamp" for "commitTimestamp"
+func (f *FieldSpec) SnakeCaseName() string {
+	return ToSnakeCase(f.Name)
+}
+
+func (f *FieldSpec) IsArray() bool {
+	return strings.HasPrefix(f.Type, "[]")
+}
+
+// ElementType returns the type of the elements of an array, or the type itself otherwise
+func (f *FieldSpec) ElementType() string {
+	return strings.TrimPrefix(f.Type, "[]")
+}
+
+// IsStruct returns true if the type, or the element type of an array, is a struct
+func (f *FieldSpec) IsStruct() bool {
+	t := f.ElementType()
+	if _, ok := primitiveTypes[t]; ok {
+		return false
+	}
+	return t != "" && unicode.IsUpper(rune(t[0]))
+}
+
+// ToSnakeCase converts a camel case name into a snake case name the way Kafka does
+func ToSnakeCase(name string) string {
+	var sb strings.Builder
+	prevWasCapitalized := true
+	for _, c := range name {
+		if unicode.IsUpper(c) {
+			if !prevWasCapitalized {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(unicode.ToLower(c))
+			prevWasCapitalized = true
+		} else {
+			sb.WriteRune(c)
+			prevWasCapitalized = false
+		}
+	}
+	return sb.String()
+}
+
+// stripComments removes the "//" line comments outside of string literals
+func stripComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		if c == '/' && i+1 < len(data) && data[i+1] == '/' {
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+		}
+		out = append(out, c)
+	}
+	return out
+}
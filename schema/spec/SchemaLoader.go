@@ -0,0 +1,218 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	kafkaschema "kafka_schema/schema"
+	"strconv"
+	"strings"
+)
+
+var primitiveTypes = map[string]struct{}{
+	"bool":    {},
+	"int8":    {},
+	"int16":   {},
+	"uint16":  {},
+	"int32":   {},
+	"uint32":  {},
+	"int64":   {},
+	"float64": {},
+	"uuid":    {},
+	"string":  {},
+	"bytes":   {},
+	"records": {},
+}
+
+// LoadSchemas parses the message specification stored in the file at path and
+// returns its schema for every valid version
+func LoadSchemas(path string) (map[int]*kafkaschema.Schema, error) {
+	message, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return message.Schemas()
+}
+
+// Schemas returns the schema of every valid version of the message
+func (m *MessageSpec) Schemas() (map[int]*kafkaschema.Schema, error) {
+	vs, err := m.VersionedSchema()
+	if err != nil {
+		return nil, err
+	}
+	return vs.Schemas(), nil
+}
+
+// Schema returns the schema of the message at the given version
+func (m *MessageSpec) Schema(version int16) (*kafkaschema.Schema, error) {
+	vs, err := m.VersionedSchema()
+	if err != nil {
+		return nil, err
+	}
+	return vs.Schema(version)
+}
+
+// StructSchema returns the schema of the struct type name at the given version of the
+// message, e.g. the schema of the elements of an array of structs
+func (m *MessageSpec) StructSchema(name string, version int16) (*kafkaschema.Schema, error) {
+	vs, err := m.structType(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", m.Name, err)
+	}
+	return vs.Schema(version)
+}
+
+// VersionedSchema returns the definition of the message for all its valid versions
+func (m *MessageSpec) VersionedSchema() (*kafkaschema.VersionedSchema, error) {
+	return m.versionedSchema(m.Name, m.Fields)
+}
+
+func (m *MessageSpec) versionedSchema(name string, fields []*FieldSpec) (*kafkaschema.VersionedSchema, error) {
+	vfs := make([]*kafkaschema.VersionedField, 0, len(fields))
+	for _, f := range fields {
+		vf, err := m.field(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: field %s: %v", name, f.Name, err)
+		}
+		vfs = append(vfs, vf)
+	}
+	return kafkaschema.NewVersionedSchema(name, m.validVersions, m.flexibleVersions, vfs...)
+}
+
+func (m *MessageSpec) field(f *FieldSpec) (*kafkaschema.VersionedField, error) {
+	var vf *kafkaschema.VersionedField
+	switch {
+	case f.IsStruct():
+		element, err := m.structType(f.ElementType())
+		if err != nil {
+			return nil, err
+		}
+		if f.IsArray() {
+			vf = kafkaschema.NewVersionedArrayField(f.SnakeCaseName(), element, f.versions)
+		} else {
+			vf = kafkaschema.NewVersionedStructField(f.SnakeCaseName(), element, f.versions)
+		}
+	case f.IsArray():
+		element, err := primitiveType(f.ElementType())
+		if err != nil {
+			return nil, err
+		}
+		vf = kafkaschema.NewVersionedField(f.SnakeCaseName(), kafkaschema.NewArrayOf(element), f.versions)
+	default:
+		t, err := primitiveType(f.Type)
+		if err != nil {
+			return nil, err
+		}
+		vf = kafkaschema.NewVersionedField(f.SnakeCaseName(), t, f.versions)
+	}
+	vf.WithDoc(f.About).WithNullableVersions(f.nullableVersions)
+	if !f.taggedVersions.IsEmpty() {
+		vf.WithTag(*f.Tag, f.taggedVersions)
+	}
+	if len(f.Default) > 0 {
+		defaultValue, err := ParseDefault(f, !f.nullableVersions.IsEmpty())
+		if err != nil {
+			return nil, err
+		}
+		vf.WithDefault(defaultValue)
+	}
+	return vf, nil
+}
+
+func (m *MessageSpec) structType(name string) (*kafkaschema.VersionedSchema, error) {
+	s, ok := m.structs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown struct type %s", name)
+	}
+	return m.versionedSchema(name, s.Fields)
+}
+
+// primitiveType returns the type of a primitive in the versions which are neither nullable
+// nor flexible, the versioned fields derive the nullable and compact types from it
+func primitiveType(name string) (kafkaschema.Type, error) {
+	switch name {
+	case "bool":
+		return kafkaschema.BOOLEAN, nil
+	case "int8":
+		return kafkaschema.INT8, nil
+	case "int16":
+		return kafkaschema.INT16, nil
+	case "uint16":
+		return kafkaschema.UINT16, nil
+	case "int32":
+		return kafkaschema.INT32, nil
+	case "uint32":
+		return kafkaschema.UINT32, nil
+	case "int64":
+		return kafkaschema.INT64, nil
+	case "float64":
+		return kafkaschema.FLOAT64, nil
+	case "uuid":
+		return kafkaschema.UUID, nil
+	case "string":
+		return kafkaschema.STRING, nil
+	case "bytes":
+		return kafkaschema.BYTES, nil
+	case "records":
+		return kafkaschema.NullableBytes, nil
+	default:
+		return nil, fmt.Errorf("unknown type %s", name)
+	}
+}
+
+// ParseDefault converts the default of a field, which Kafka writes either as a JSON
+// string or as a JSON literal, into a value of the type of the field
+func ParseDefault(f *FieldSpec, nullable bool) (interface{}, error) {
+	var raw interface{}
+	decoder := json.NewDecoder(bytes.NewReader(f.Default))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid default %s: %v", string(f.Default), err)
+	}
+	str := fmt.Sprint(raw)
+	if f.Type != "string" {
+		str = strings.TrimSpace(str)
+	}
+	if raw == nil || str == "null" {
+		if !nullable && f.Type != "records" {
+			return nil, fmt.Errorf("default null of a non nullable field")
+		}
+		return nil, nil
+	}
+	if f.IsArray() || f.IsStruct() {
+		return nil, fmt.Errorf("only null defaults are supported for %s", f.Type)
+	}
+	switch f.Type {
+	case "bool":
+		return strconv.ParseBool(str)
+	case "int8":
+		v, err := parseInt(str, 8)
+		return int8(v), err
+	case "int16":
+		v, err := parseInt(str, 16)
+		return int16(v), err
+	case "int32":
+		v, err := parseInt(str, 32)
+		return int32(v), err
+	case "int64":
+		return parseInt(str, 64)
+	case "uint16":
+		v, err := strconv.ParseUint(str, 0, 16)
+		return uint16(v), err
+	case "uint32":
+		v, err := strconv.ParseUint(str, 0, 32)
+		return uint32(v), err
+	case "float64":
+		return strconv.ParseFloat(str, 64)
+	case "string":
+		return str, nil
+	case "uuid":
+		return kafkaschema.ParseUuid(str)
+	default:
+		return nil, fmt.Errorf("defaults are not supported for %s", f.Type)
+	}
+}
+
+func parseInt(str string, bitSize int) (int64, error) {
+	return strconv.ParseInt(str, 0, bitSize)
+}
@@ -0,0 +1,92 @@
+package spec
+
+import (
+	kafkaschema "kafka_schema/schema"
+	"testing"
+	"testing/fstest"
+)
+
+const testSpec = `
+// a comment
+{
+  "type": "data",
+  "name": "TestValue",
+  "validVersions": "0-2",
+  "flexibleVersions": "2+",
+  "fields": [
+    { "name": "groupId", "type": "string", "versions": "0+" },
+    { "name": "leaderEpoch", "type": "int32", "versions": "1+", "default": -1 },
+    { "name": "members", "type": "[]Member", "versions": "0+", "fields": [
+      { "name": "memberId", "type": "string", "versions": "0+" }
+    ]},
+    { "name": "extra", "type": "int32", "versions": "2+", "taggedVersions": "2+", "tag": 0 }
+  ]
+}`
+
+func TestLoadFSSchemas(t *testing.T) {
+	m, err := LoadFS(fstest.MapFS{"TestValue.json": {Data: []byte(testSpec)}}, "TestValue.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := m.Schemas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int]string{
+		0: "{group_id:STRING,members:ARRAY({member_id:STRING})}",
+		1: "{group_id:STRING,leader_epoch:INT32,members:ARRAY({member_id:STRING})}",
+		2: "{group_id:COMPACT_STRING,leader_epoch:INT32,members:COMPACT_ARRAY({member_id:COMPACT_STRING,_tagged_fields:TAGGED_FIELDS}),_tagged_fields:TAGGED_FIELDS}",
+	}
+	if len(schemas) != len(want) {
+		t.Fatalf("Schemas() returned %d versions, want %d", len(schemas), len(want))
+	}
+	for version, schema := range schemas {
+		if got := schema.String(); got != want[version] {
+			t.Errorf("version %d = %s, want %s", version, got, want[version])
+		}
+	}
+
+	vs, err := m.VersionedSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range vs.Fields() {
+		if field.Name() != "extra" {
+			continue
+		}
+		if tag, versions := field.Tag(); tag != 0 || versions != kafkaschema.VersionsFrom(2) {
+			t.Errorf("Tag() of extra = %d, %s, want 0, 2+", tag, versions)
+		}
+	}
+
+	member, err := m.StructSchema("Member", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := member.String(), "{member_id:COMPACT_STRING,_tagged_fields:TAGGED_FIELDS}"; got != want {
+		t.Errorf("StructSchema(Member, 2) = %s, want %s", got, want)
+	}
+	if _, err := m.StructSchema("Member", 3); err == nil {
+		t.Error("StructSchema(Member, 3) of a message without version 3 succeeded")
+	}
+}
+
+func TestParseInvalidSpecs(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "open-ended valid versions", spec: `{"name": "M", "validVersions": "0+", "fields": []}`},
+		{name: "no valid versions", spec: `{"name": "M", "validVersions": "none", "fields": []}`},
+		{name: "no name", spec: `{"validVersions": "0", "fields": []}`},
+		{name: "tagged field without tag", spec: `{"name": "M", "validVersions": "0", "flexibleVersions": "0+",
+			"fields": [{"name": "a", "type": "int32", "versions": "0+", "taggedVersions": "0+"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.spec)); err == nil {
+				t.Error("Parse() succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package spec
+
+import kafkaschema "kafka_schema/schema"
+
+// Versions is the version range of the schema package, "none", "3", "1-3" or "2+"
+type Versions = kafkaschema.Versions
+
+var (
+	NoVersions  = kafkaschema.NoVersions
+	AllVersions = kafkaschema.AllVersions
+)
+
+func ParseVersions(str string) (Versions, error) {
+	return kafkaschema.ParseVersions(str)
+}